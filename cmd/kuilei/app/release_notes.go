@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/oauth2"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/releasenotes"
+	"github.com/airconduct/kuilei/pkg/signals"
+)
+
+func NewReleaseNotes() *cobra.Command {
+	opts := &releaseNotesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "release-notes",
+		Short: "generate release notes from merged pull requests",
+		Example: "  kuilei release-notes --repo airconduct/kuilei --from v0.1.0 --to v0.2.0\n" +
+			"  kuilei release-notes --repo airconduct/kuilei --since 2023-01-01 --format json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(args); err != nil {
+				return err
+			}
+			return opts.Run(signals.SetupSignalContext(), cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+type releaseNotesOptions struct {
+	repo       string
+	branch     string
+	from, to   string
+	since      string
+	until      string
+	format     string
+	token      string
+	graphqlURL string
+
+	opts releasenotes.Options
+}
+
+func (opts *releaseNotesOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&opts.repo, "repo", "", "Repo in the form of owner/name")
+	flags.StringVar(&opts.branch, "branch", "", "Base branch of the pull requests, all branches if empty")
+	flags.StringVar(&opts.from, "from", "", "Collect pull requests merged by the commits after this branch or tag")
+	flags.StringVar(&opts.to, "to", "", "Collect pull requests merged by the commits up to this ref, the default branch if empty")
+	flags.StringVar(&opts.since, "since", "", "Collect pull requests merged since this date, e.g. 2023-01-02 or 2023-01-02T15:04:05Z")
+	flags.StringVar(&opts.until, "until", "", "Collect pull requests merged before this date, now if empty")
+	flags.StringVar(&opts.format, "format", "markdown", "Output format: markdown | json")
+	flags.StringVar(&opts.token, "github-token", os.Getenv("GITHUB_TOKEN"), "GitHub token, defaults to $GITHUB_TOKEN")
+	flags.StringVar(&opts.graphqlURL, "github-graphql-url", "https://api.github.com/graphql", "GitHub GraphQL API URL")
+}
+
+func (opts *releaseNotesOptions) Validate(args []string) error {
+	owner, name, ok := strings.Cut(opts.repo, "/")
+	if !ok || owner == "" || name == "" {
+		return fmt.Errorf("--repo must be in the form of owner/name, got %q", opts.repo)
+	}
+	if opts.format != "markdown" && opts.format != "json" {
+		return fmt.Errorf("unsupported format %q", opts.format)
+	}
+	if opts.from == "" && opts.since == "" {
+		return fmt.Errorf("either --from or --since is required")
+	}
+	if opts.token == "" {
+		return fmt.Errorf("--github-token or $GITHUB_TOKEN is required")
+	}
+	since, err := parseDate(opts.since)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseDate(opts.until)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+	opts.opts = releasenotes.Options{
+		Repo:   plugins.GitRepo{Name: name, Owner: plugins.GitUser{Name: owner}},
+		Branch: opts.branch,
+		From:   opts.from, To: opts.to,
+		Since: since, Until: until,
+	}
+	return nil
+}
+
+func (opts *releaseNotesOptions) Run(ctx context.Context, out io.Writer) error {
+	tc := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: opts.token}))
+	client := pluginhelpers.GitSearchClientFromGithub(
+		githubv4.NewEnterpriseClient(opts.graphqlURL, &http.Client{Transport: tc.Transport}),
+	)
+	notes, err := releasenotes.Collect(ctx, client, opts.opts)
+	if err != nil {
+		return err
+	}
+	output := notes.Markdown()
+	if opts.format == "json" {
+		if output, err = notes.JSON(); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(out, output)
+	return err
+}
+
+// parseDate parses a date or an RFC3339 time, an empty string is the zero time.
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
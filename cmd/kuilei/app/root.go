@@ -16,6 +16,7 @@ func New() *cobra.Command {
 	opts.AddFlags(pflag.CommandLine)
 	cmd.AddCommand(
 		NewHook(),
+		NewReleaseNotes(),
 	)
 	return cmd
 }
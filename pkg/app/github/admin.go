@@ -0,0 +1,57 @@
+package github
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/scheduler"
+)
+
+type configCacheLister interface {
+	Entries() []pluginhelpers.ConfigCacheEntry
+}
+
+type scheduleLister interface {
+	Jobs() []scheduler.JobStatus
+}
+
+// registerAdminHandlers registers the admin endpoints, which require the bearer token:
+//   - <prefix>/caches lists the keys, ages and source SHAs of cached configs of each kind
+//   - <prefix>/schedules lists the jobs of periodic plugins with their next and last runs
+func registerAdminHandlers(
+	mux *http.ServeMux, prefix, token string, caches map[string]configCacheLister, schedules scheduleLister,
+) {
+	handle := func(name string, handler http.HandlerFunc) {
+		mux.HandleFunc(path.Join(prefix, name), func(w http.ResponseWriter, r *http.Request) {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Method != http.MethodGet {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			handler(w, r)
+		})
+	}
+	handle("caches", func(w http.ResponseWriter, r *http.Request) {
+		out := map[string][]pluginhelpers.ConfigCacheEntry{}
+		for kind, cache := range caches {
+			entries := cache.Entries()
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+			out[kind] = entries
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out)
+	})
+	handle("schedules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(schedules.Jobs())
+	})
+}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/airconduct/go-probot"
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// botResolver resolves the user the App acts as, "<app slug>[bot]" for a GitHub App, or the
+// user of the token otherwise. The user is resolved once and shared by all installations.
+type botResolver struct {
+	creds appCredentials
+
+	mutex sync.Mutex
+	user  *plugins.GitUser
+}
+
+// client returns a BotClient which resolves the user with gh if the App authenticates with a token.
+func (r *botResolver) client(gh *probot.GitHubClient) plugins.BotClient {
+	return pluginhelpers.MakeBotClient(func(ctx context.Context) (plugins.GitUser, error) {
+		return r.resolve(ctx, gh)
+	})
+}
+
+func (r *botResolver) resolve(ctx context.Context, gh *probot.GitHubClient) (plugins.GitUser, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.user != nil {
+		return *r.user, nil
+	}
+	var login string
+	if r.creds.isApp() {
+		_, appClient, err := r.creds.appClient()
+		if err != nil {
+			return plugins.GitUser{}, err
+		}
+		app, _, err := appClient.Apps.Get(ctx, "")
+		if err != nil {
+			return plugins.GitUser{}, fmt.Errorf("failed to get the App, %w", err)
+		}
+		login = app.GetSlug() + "[bot]"
+	} else {
+		user, _, err := gh.Users.Get(ctx, "")
+		if err != nil {
+			return plugins.GitUser{}, fmt.Errorf("failed to get the user of the token, %w", err)
+		}
+		login = user.GetLogin()
+	}
+	r.user = &plugins.GitUser{Name: login}
+	return *r.user, nil
+}
@@ -1,9 +1,17 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/airconduct/go-probot"
 	"github.com/airconduct/kuilei/pkg/app"
 	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/scheduler"
 	"github.com/airconduct/kuilei/pkg/plugins"
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
@@ -11,40 +19,115 @@ import (
 
 func New() app.Builder[probot.GitHubClient] {
 	return &githubAppBuilder{
-		githubApp:         probot.NewGitHubAPP(),
-		pluginConfigCache: pluginhelpers.NewConfigCache[plugins.Configuration](),
-		ownersConfigCache: pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](),
+		githubApp: probot.NewGitHubAPP(),
 	}
 }
 
 type githubAppBuilder struct {
-	githubApp         probot.App[probot.GitHubClient]
-	configPath        string
-	ownersFile        string
-	pluginConfigCache pluginhelpers.ConfigCache[plugins.Configuration]
-	ownersConfigCache pluginhelpers.ConfigCache[plugins.OwnersConfiguration]
+	githubApp          probot.App[probot.GitHubClient]
+	flags              *pflag.FlagSet
+	configPath         string
+	ownersFile         string
+	adminPath          string
+	adminTokenFile     string
+	configCacheOptions pluginhelpers.ConfigCacheOptions
+	schedulerOptions   scheduler.Options
 }
 
 var _ app.Builder[probot.GitHubClient] = &githubAppBuilder{}
 
 func (b *githubAppBuilder) BindFlags(flags *pflag.FlagSet) {
+	b.flags = flags
 	b.githubApp.AddFlags(flags)
 	flags.StringVar(&b.configPath, "config-path", ".github/kuilei.yml", "config path for kuilei App in git repo")
 	flags.StringVar(&b.ownersFile, "owners-file", "OWNERS", "owners file name")
+	flags.StringVar(&b.adminPath, "admin-path", "", "path prefix of admin endpoints, e.g. /admin, empty to disable them")
+	flags.StringVar(&b.adminTokenFile, "admin-token-file", "", "file of the bearer token required by admin endpoints, required if admin-path is set")
+	flags.IntVar(&b.configCacheOptions.Size, "config-cache-size", 1000, "max number of cached plugin configs and of repos with cached OWNERS files, 0 means unbounded")
+	flags.DurationVar(&b.configCacheOptions.TTL, "config-cache-ttl", 24*time.Hour, "max age of cached plugin configs and OWNERS files, 0 means never expire")
+	flags.DurationVar(&b.schedulerOptions.Stagger, "schedule-stagger", 5*time.Minute, "max delay added to the scheduled runs of periodic plugins to spread them over time")
+	flags.DurationVar(&b.schedulerOptions.Timeout, "schedule-timeout", 10*time.Minute, "max duration of each run of periodic plugins, 0 means no timeout")
 }
 func (b *githubAppBuilder) Build() (probot.App[probot.GitHubClient], error) {
+	// Validate the flags before starting anything in background
+	adminToken, err := b.readAdminToken()
+	if err != nil {
+		return nil, err
+	}
+	creds, err := appCredentialsFromFlags(b.flags)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginConfigCache := pluginhelpers.NewConfigCache[plugins.Configuration](
+		b.configCacheOptions, pluginhelpers.ConfigCacheOptions{SyncKind: pluginhelpers.PluginConfigSyncKind},
+	)
+	ownersConfigCache := pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](
+		b.configCacheOptions, pluginhelpers.ConfigCacheOptions{SyncKind: pluginhelpers.OwnersSyncKind},
+	)
+	ownersAliasesCache := pluginhelpers.NewConfigCache[plugins.OwnersAliases](b.configCacheOptions)
+	periodicScheduler := scheduler.New(b.schedulerOptions)
+	periodicScheduler.Start(context.Background())
+	bots := &botResolver{creds: creds}
+	logger := logr.Discard()
+	if l, ok := b.githubApp.(interface{ GetLogger() logr.Logger }); ok {
+		logger = l.GetLogger()
+	}
+	go func() {
+		logger := logger.WithName("scheduler")
+		if err := seedScheduler(context.Background(), logger, creds, periodicScheduler,
+			func(client *probot.GitHubClient, graphql probot.GitGraphQLClient) plugins.ClientSets {
+				pluginClient := pluginhelpers.PluginConfigClientFromGithub(client, b.configPath, pluginConfigCache)
+				return newClientSets(
+					b.ownersFile, ownersConfigCache, ownersAliasesCache, bots,
+					client, graphql, pluginClient, func() logr.Logger { return logger },
+				)
+			},
+		); err != nil {
+			logger.Error(err, "Failed to seed the scheduler")
+		}
+	}()
+	if b.adminPath != "" {
+		registerAdminHandlers(b.githubApp.ServeMux(), b.adminPath, adminToken, map[string]configCacheLister{
+			"plugin-config":  pluginConfigCache,
+			"owners":         ownersConfigCache,
+			"owners-aliases": ownersAliasesCache,
+		}, periodicScheduler)
+	}
 	return b.complete(
 		b.githubApp, b.configPath, b.ownersFile,
-		b.pluginConfigCache, b.ownersConfigCache,
+		pluginConfigCache, ownersConfigCache, ownersAliasesCache, bots, periodicScheduler,
 	), nil
 }
 
+// readAdminToken returns the token of the admin endpoints, empty if they are disabled.
+func (b *githubAppBuilder) readAdminToken() (string, error) {
+	if b.adminPath == "" {
+		return "", nil
+	}
+	if b.adminTokenFile == "" {
+		return "", errors.New("admin-token-file must be set when admin-path is set")
+	}
+	rawToken, err := os.ReadFile(b.adminTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read admin token file, %w", err)
+	}
+	adminToken := strings.TrimSpace(string(rawToken))
+	if adminToken == "" {
+		return "", errors.New("admin token file is empty")
+	}
+	return adminToken, nil
+}
+
 func (*githubAppBuilder) complete(
 	githubApp probot.App[probot.GitHubClient],
 	configPath string,
 	ownersFile string,
 	pluginConfigCache pluginhelpers.ConfigCache[plugins.Configuration],
-	ownersConfigCache pluginhelpers.ConfigCache[plugins.OwnersConfiguration],
+	ownersConfigCache pluginhelpers.NearestConfigCache[plugins.OwnersConfiguration],
+	ownersAliasesCache pluginhelpers.ConfigCache[plugins.OwnersAliases],
+	bots *botResolver,
+	periodicScheduler *scheduler.Scheduler,
 ) probot.App[probot.GitHubClient] {
 	// Listen for GitHub issues events
 	githubApp.On(probot.GitHub.Issues).WithHandler(probot.GitHub.Issues.Handler(func(ctx probot.GitHubIssuesContext) {
@@ -56,17 +139,10 @@ func (*githubAppBuilder) complete(
 		cfg, err := pluginClient.GetConfig(payload.Repo.Owner.GetLogin(), payload.Repo.GetName())
 		ctx.Must(err)
 		// Execute all plugins in config
-		for _, p := range cfg.Plugins {
-			plugin := plugins.GetGitCommentPlugin(p.Name, getClientSets(ownersFile, ownersConfigCache, ctx, pluginClient), p.Args...)
-			if plugin == nil {
-				ctx.Logger().Info("Plugin not found", "name", p.Name)
-				continue
-			}
-			// Execute plugin
-			if err := plugin.Do(ctx, pluginhelpers.GitCommentEventFromGithubIssuesEvent(payload)); err != nil {
-				ctx.Logger().Error(err, "Failed to execute plugin", "name", plugin.Name())
-			}
-		}
+		doGitCommentPlugins(
+			ctx, cfg, periodicScheduler, getClientSets(ownersFile, ownersConfigCache, ownersAliasesCache, bots, ctx, pluginClient),
+			probot.GitHub.Issues.Type(), pluginhelpers.GitCommentEventFromGithubIssuesEvent(payload),
+		)
 	}))
 	// Listen for GitHub issue comment events
 	githubApp.On(probot.GitHub.IssueComment).WithHandler(probot.GitHub.IssueComment.Handler(func(ctx probot.GitHubIssueCommentContext) {
@@ -78,17 +154,10 @@ func (*githubAppBuilder) complete(
 		cfg, err := pluginClient.GetConfig(payload.Repo.Owner.GetLogin(), payload.Repo.GetName())
 		ctx.Must(err)
 		// Execute all plugins in config
-		for _, p := range cfg.Plugins {
-			plugin := plugins.GetGitCommentPlugin(p.Name, getClientSets(ownersFile, ownersConfigCache, ctx, pluginClient), p.Args...)
-			if plugin == nil {
-				ctx.Logger().Info("Plugin not found", "name", p.Name)
-				continue
-			}
-			// Execute plugin
-			if err := plugin.Do(ctx, pluginhelpers.GitCommentEventFromGithubIssueCommentEvent(payload)); err != nil {
-				ctx.Logger().Error(err, "Failed to execute plugin", "name", plugin.Name())
-			}
-		}
+		doGitCommentPlugins(
+			ctx, cfg, periodicScheduler, getClientSets(ownersFile, ownersConfigCache, ownersAliasesCache, bots, ctx, pluginClient),
+			probot.GitHub.IssueComment.Type(), pluginhelpers.GitCommentEventFromGithubIssueCommentEvent(payload),
+		)
 	}))
 	// Listen for GitHub pull request events
 	githubApp.On(probot.GitHub.PullRequest).WithHandler(probot.GitHub.PullRequest.Handler(func(ctx probot.GitHubPullRequestContext) {
@@ -99,17 +168,10 @@ func (*githubAppBuilder) complete(
 		cfg, err := pluginClient.GetConfig(payload.Repo.Owner.GetLogin(), payload.Repo.GetName())
 		ctx.Must(err)
 		// Execute all plugins in config
-		for _, p := range cfg.Plugins {
-			plugin := plugins.GetGitCommentPlugin(p.Name, getClientSets(ownersFile, ownersConfigCache, ctx, pluginClient), p.Args...)
-			if plugin == nil {
-				ctx.Logger().Info("Plugin not found", "name", p.Name)
-				continue
-			}
-			// Execute plugin
-			if err := plugin.Do(ctx, pluginhelpers.GitCommentEventFromGithubPullRequestEvent(payload)); err != nil {
-				ctx.Logger().Error(err, "Failed to execute plugin", "name", plugin.Name())
-			}
-		}
+		doGitCommentPlugins(
+			ctx, cfg, periodicScheduler, getClientSets(ownersFile, ownersConfigCache, ownersAliasesCache, bots, ctx, pluginClient),
+			probot.GitHub.PullRequest.Type(), pluginhelpers.GitCommentEventFromGithubPullRequestEvent(payload),
+		)
 	}))
 	// Listen for GitHub pull request review events
 	githubApp.On(
@@ -122,17 +184,11 @@ func (*githubAppBuilder) complete(
 		cfg, err := pluginClient.GetConfig(payload.Repo.Owner.GetLogin(), payload.Repo.GetName())
 		ctx.Must(err)
 
-		for _, p := range cfg.Plugins {
-			plugin := plugins.GetGitCommentPlugin(p.Name, getClientSets(ownersFile, ownersConfigCache, ctx, pluginClient), p.Args...)
-			if plugin == nil {
-				ctx.Logger().Info("Plugin not found", "name", p.Name)
-				continue
-			}
-			// Execute plugin
-			if err := plugin.Do(ctx, pluginhelpers.GitCommentEventFromGithubPullRequestReviewEvent(payload)); err != nil {
-				ctx.Logger().Error(err, "Failed to execute plugin", "name", plugin.Name())
-			}
-		}
+		// Execute all plugins in config
+		doGitCommentPlugins(
+			ctx, cfg, periodicScheduler, getClientSets(ownersFile, ownersConfigCache, ownersAliasesCache, bots, ctx, pluginClient),
+			probot.GitHub.PullRequestReview.Type(), pluginhelpers.GitCommentEventFromGithubPullRequestReviewEvent(payload),
+		)
 	}))
 	// Listen for GitHub pull request review comment events
 	githubApp.On(
@@ -145,21 +201,43 @@ func (*githubAppBuilder) complete(
 		cfg, err := pluginClient.GetConfig(payload.Repo.Owner.GetLogin(), payload.Repo.GetName())
 		ctx.Must(err)
 
-		for _, p := range cfg.Plugins {
-			plugin := plugins.GetGitCommentPlugin(p.Name, getClientSets(ownersFile, ownersConfigCache, ctx, pluginClient), p.Args...)
-			if plugin == nil {
-				ctx.Logger().Info("Plugin not found", "name", p.Name)
-				continue
-			}
-			// Execute plugin
-			if err := plugin.Do(ctx, pluginhelpers.GitCommentEventFromGithubPullRequestReviewCommentEvent(payload)); err != nil {
-				ctx.Logger().Error(err, "Failed to execute plugin", "name", plugin.Name())
-			}
-		}
+		// Execute all plugins in config
+		doGitCommentPlugins(
+			ctx, cfg, periodicScheduler, getClientSets(ownersFile, ownersConfigCache, ownersAliasesCache, bots, ctx, pluginClient),
+			probot.GitHub.PullRequestReviewComment.Type(), pluginhelpers.GitCommentEventFromGithubPullRequestReviewCommentEvent(payload),
+		)
 	}))
 	// Listen for GitHub push events
 	githubApp.On(probot.GitHub.Push).WithHandler(probot.GitHub.Push.Handler(func(ctx probot.GitHubPushContext) {
-		// TODO: Implement
+		// Reload the config, OWNERS and OWNERS_ALIASES files changed by the push
+		if err := pluginhelpers.ReloadConfigFromGithubPush(
+			ctx, ctx.Client(), ctx.Payload(), configPath, ownersFile,
+			pluginConfigCache, ownersConfigCache, ownersAliasesCache,
+		); err != nil {
+			ctx.Logger().Error(err, "Failed to reload config")
+		}
+	}))
+	// Listen for GitHub installation deleted events
+	githubApp.On(probot.GitHub.Installation.Deleted).WithHandler(probot.GitHub.Installation.Handler(func(ctx probot.GitHubInstallationContext) {
+		payload := ctx.Payload()
+		for _, repo := range payload.Repositories {
+			owner, name := splitRepoFullName(repo.GetFullName(), payload.GetInstallation().GetAccount().GetLogin())
+			ctx.Logger().Info("Drop caches of uninstalled repo", "owner", owner, "repo", name)
+			pluginhelpers.ForgetRepo(owner, name, pluginConfigCache, ownersConfigCache, ownersAliasesCache)
+			periodicScheduler.Forget(owner, name)
+		}
+	}))
+	// Listen for GitHub installation repositories removed events
+	githubApp.On(
+		probot.GitHub.InstallationRepositories.Removed,
+	).WithHandler(probot.GitHub.InstallationRepositories.Handler(func(ctx probot.GitHubInstallationRepositoriesContext) {
+		payload := ctx.Payload()
+		for _, repo := range payload.RepositoriesRemoved {
+			owner, name := splitRepoFullName(repo.GetFullName(), payload.GetInstallation().GetAccount().GetLogin())
+			ctx.Logger().Info("Drop caches of removed repo", "owner", owner, "repo", name)
+			pluginhelpers.ForgetRepo(owner, name, pluginConfigCache, ownersConfigCache, ownersAliasesCache)
+			periodicScheduler.Forget(owner, name)
+		}
 	}))
 	// Listen for GitHub status events
 	githubApp.On(probot.GitHub.Status).WithHandler(probot.GitHub.Status.Handler(func(ctx probot.GitHubStatusContext) {
@@ -169,22 +247,83 @@ func (*githubAppBuilder) complete(
 	return githubApp
 }
 
+// doGitCommentPlugins executes the git comment plugins in config whose conditions match the event,
+// and registers the periodic plugins in config to the scheduler with the clients of the event.
+func doGitCommentPlugins[PT any](
+	ctx probot.ProbotContext[probot.GitHubClient, PT],
+	cfg plugins.Configuration,
+	periodicScheduler *scheduler.Scheduler,
+	clientSets plugins.ClientSets,
+	eventType string,
+	e plugins.GitCommentEvent,
+) {
+	periodicScheduler.Register(e.Repo, cfg, clientSets)
+	matcher := pluginhelpers.NewPluginConditionMatcher(clientSets.GitPRClient, eventType, e)
+	for _, p := range cfg.Plugins {
+		plugin := plugins.GetGitCommentPlugin(p.Name, clientSets, p.Args...)
+		if plugin == nil {
+			ctx.Logger().Info("Plugin not found", "name", p.Name)
+			continue
+		}
+		matched, err := matcher.Match(ctx, p.Conditions)
+		if err != nil {
+			ctx.Logger().Error(err, "Failed to match plugin conditions", "name", plugin.Name())
+			continue
+		}
+		if !matched {
+			ctx.Logger().V(1).Info("Plugin conditions not matched, skip", "name", plugin.Name())
+			continue
+		}
+		// Execute plugin
+		if err := plugin.Do(ctx, e); err != nil {
+			ctx.Logger().Error(err, "Failed to execute plugin", "name", plugin.Name())
+		}
+	}
+}
+
+// splitRepoFullName splits "owner/name", the owner defaults to defaultOwner if it is missing.
+func splitRepoFullName(fullName, defaultOwner string) (owner, name string) {
+	if idx := strings.Index(fullName, "/"); idx >= 0 {
+		return fullName[:idx], fullName[idx+1:]
+	}
+	return defaultOwner, fullName
+}
+
 func getClientSets[PT any](
 	ownersFile string,
-	ownersConfigCache pluginhelpers.ConfigCache[plugins.OwnersConfiguration],
+	ownersConfigCache pluginhelpers.NearestConfigCache[plugins.OwnersConfiguration],
+	ownersAliasesCache pluginhelpers.ConfigCache[plugins.OwnersAliases],
+	bots *botResolver,
 	ctx probot.ProbotContext[probot.GitHubClient, PT],
 	pluginClient plugins.PluginConfigClient,
 ) plugins.ClientSets {
 	logger := ctx.Logger()
+	return newClientSets(
+		ownersFile, ownersConfigCache, ownersAliasesCache, bots,
+		ctx.Client(), ctx.GraphQL(), pluginClient, func() logr.Logger { return logger },
+	)
+}
+
+func newClientSets(
+	ownersFile string,
+	ownersConfigCache pluginhelpers.NearestConfigCache[plugins.OwnersConfiguration],
+	ownersAliasesCache pluginhelpers.ConfigCache[plugins.OwnersAliases],
+	bots *botResolver,
+	client *probot.GitHubClient,
+	graphql probot.GitGraphQLClient,
+	pluginClient plugins.PluginConfigClient,
+	getLogger func() logr.Logger,
+) plugins.ClientSets {
 	return plugins.ClientSets{
-		GitIssueClient:     pluginhelpers.GitIssueClientFromGithub(ctx.Client()),
-		GitPRClient:        pluginhelpers.GitPRClientFromGithub(ctx.Client()),
+		GitIssueClient:     pluginhelpers.GitIssueClientFromGithub(client),
+		GitPRClient:        pluginhelpers.GitPRClientFromGithub(client),
 		PluginConfigClient: pluginClient,
-		OwnersClient:       pluginhelpers.OwnersClientFromGithub(ctx.Client(), ownersFile, ownersConfigCache),
-		GitRepoClient:      pluginhelpers.GitRepoClientFromGithub(ctx.Client()),
-		GitSearchClient:    pluginhelpers.GitSearchClientFromGithub(ctx.GraphQL()),
-		LoggerClient: pluginhelpers.MakeLoggerClient(func() logr.Logger {
-			return logger
-		}),
+		OwnersClient:       pluginhelpers.OwnersClientFromGithub(client, ownersFile, ownersConfigCache, ownersAliasesCache),
+		GitRepoClient:      pluginhelpers.GitRepoClientFromGithub(client),
+		GitSearchClient:    pluginhelpers.GitSearchClientFromGithub(graphql),
+		GitOrgClient:       pluginhelpers.GitOrgClientFromGithub(client),
+		GitLocalClient:     pluginhelpers.GitLocalClientFromGithub(client),
+		LoggerClient:       pluginhelpers.MakeLoggerClient(getLogger),
+		BotClient:          bots.client(client),
 	}
 }
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/go-logr/logr"
+	"github.com/google/go-github/v48/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/go-probot"
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/scheduler"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// appCredentials are the credentials of the GitHub App, read from the flags of the probot App,
+// which keeps its installation clients private.
+type appCredentials struct {
+	appID          int64
+	privateKeyFile string
+	baseURL        string
+	uploadURL      string
+	graphqlURL     string
+}
+
+// isApp returns true if the App authenticates as a GitHub App rather than with a static token.
+func (creds appCredentials) isApp() bool {
+	return creds.privateKeyFile != "" && creds.appID != 0
+}
+
+// appClient returns a client authenticated as the GitHub App itself, which can only call the
+// endpoints of the App, e.g. to list its installations.
+func (creds appCredentials) appClient() (*ghinstallation.AppsTransport, *github.Client, error) {
+	privateKey, err := os.ReadFile(creds.privateKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read private key file, %w", err)
+	}
+	appTransport, err := ghinstallation.NewAppsTransport(http.DefaultTransport, creds.appID, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	appTransport.BaseURL = strings.TrimSuffix(creds.baseURL, "/")
+	appClient, err := github.NewEnterpriseClient(creds.baseURL, creds.uploadURL, &http.Client{Transport: appTransport})
+	if err != nil {
+		return nil, nil, err
+	}
+	return appTransport, appClient, nil
+}
+
+func appCredentialsFromFlags(flags *pflag.FlagSet) (appCredentials, error) {
+	value := func(name string) string {
+		if flags == nil {
+			return ""
+		}
+		if f := flags.Lookup(name); f != nil {
+			return f.Value.String()
+		}
+		return ""
+	}
+	creds := appCredentials{
+		privateKeyFile: value("github.private-key-file"),
+		baseURL:        value("github.base-url"),
+		uploadURL:      value("github.upload-url"),
+		graphqlURL:     value("github.graphql-url"),
+	}
+	if appID := value("github.appid"); appID != "" {
+		var err error
+		if creds.appID, err = strconv.ParseInt(appID, 10, 64); err != nil {
+			return appCredentials{}, fmt.Errorf("invalid github.appid %q, %w", appID, err)
+		}
+	}
+	return creds, nil
+}
+
+// seedScheduler registers the periodic plugins of all repos the App is installed in, with clients
+// scoped to their installations, so that the jobs survive restarts without waiting for a webhook
+// event of each repo. It does nothing if the App authenticates with a static token.
+func seedScheduler(
+	ctx context.Context, logger logr.Logger, creds appCredentials, periodicScheduler *scheduler.Scheduler,
+	newClientSets func(client *probot.GitHubClient, graphql probot.GitGraphQLClient) plugins.ClientSets,
+) error {
+	if !creds.isApp() {
+		logger.Info("Skip seeding the scheduler, the App is not authenticated with a private key")
+		return nil
+	}
+	appTransport, appClient, err := creds.appClient()
+	if err != nil {
+		return err
+	}
+
+	var installations []*github.Installation
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := appClient.Apps.ListInstallations(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list installations, %w", err)
+		}
+		installations = append(installations, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for _, installation := range installations {
+		transport := ghinstallation.NewFromAppsTransport(appTransport, installation.GetID())
+		client, err := github.NewEnterpriseClient(creds.baseURL, creds.uploadURL, &http.Client{Transport: transport})
+		if err != nil {
+			return err
+		}
+		graphql := githubv4.NewEnterpriseClient(creds.graphqlURL, &http.Client{Transport: transport})
+		clientSets := newClientSets(client, graphql)
+		log := logger.WithValues("installation", installation.GetID())
+		if err := seedInstallation(ctx, log, client, periodicScheduler, clientSets); err != nil {
+			// Seed the other installations, the repos of this one are registered on their next event
+			log.Error(err, "Failed to seed the scheduler")
+		}
+	}
+	return nil
+}
+
+func seedInstallation(
+	ctx context.Context, logger logr.Logger, client *probot.GitHubClient,
+	periodicScheduler *scheduler.Scheduler, clientSets plugins.ClientSets,
+) error {
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.Apps.ListRepos(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list repos, %w", err)
+		}
+		for _, r := range page.Repositories {
+			repo := plugins.GitRepo{Owner: plugins.GitUser{Name: r.GetOwner().GetLogin()}, Name: r.GetName()}
+			cfg, err := clientSets.PluginConfigClient.GetConfig(repo.Owner.Name, repo.Name)
+			if err != nil {
+				// Most repos have no config file at all
+				logger.V(1).Info("Skip repo without valid config", "owner", repo.Owner.Name, "repo", repo.Name, "error", err.Error())
+				continue
+			}
+			periodicScheduler.Register(repo, cfg, clientSets)
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,17 @@
+package pluginhelpers
+
+import (
+	"context"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+func MakeBotClient(getBotUser func(ctx context.Context) (plugins.GitUser, error)) plugins.BotClient {
+	return getBotUserFunc(getBotUser)
+}
+
+type getBotUserFunc func(ctx context.Context) (plugins.GitUser, error)
+
+func (fn getBotUserFunc) BotUser(ctx context.Context) (plugins.GitUser, error) {
+	return fn(ctx)
+}
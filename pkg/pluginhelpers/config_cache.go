@@ -2,36 +2,165 @@ package pluginhelpers
 
 import (
 	"fmt"
-	"sync"
+	"strings"
+	"time"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/syncer"
 )
 
 type ConfigCache[T any] interface {
 	Get(owner, repo, path string) *T
+	// Has returns true if the config of path itself is cached.
+	Has(owner, repo, path string) bool
 	Save(owner, repo, path string, cfg *T)
+	// SaveWithSHA saves the config with the SHA of the file it is parsed from.
+	SaveWithSHA(owner, repo, path, sha string, cfg *T)
+	// SaveWithETag saves the config with the SHA and the ETag of the file it is parsed from.
+	SaveWithETag(owner, repo, path, sha, etag string, cfg *T)
+	// ETag returns the ETag of the file the cached config of path is parsed from, "" if unknown.
+	ETag(owner, repo, path string) string
+	// Touch renews the age of the config of path, when its file is confirmed to be unmodified.
+	Touch(owner, repo, path string)
+	Delete(owner, repo, path string)
+	// DeleteRepo drops all configs of a repo.
+	DeleteRepo(owner, repo string)
+	// Entries lists the cached configs.
+	Entries() []ConfigCacheEntry
+}
+
+// ConfigCacheEntry describes a cached config.
+type ConfigCacheEntry struct {
+	Key     string    `json:"key"`
+	SHA     string    `json:"sha,omitempty"`
+	SavedAt time.Time `json:"savedAt"`
+	Age     string    `json:"age"`
 }
 
-func NewConfigCache[T any]() ConfigCache[T] {
-	return &configCache[T]{}
+// ConfigCacheOptions bounds a config cache.
+type ConfigCacheOptions struct {
+	// Size is the max number of entries, entries are evicted in LRU order. Zero means unbounded.
+	Size int
+	// TTL is the max age of entries. Zero means entries never expire.
+	TTL time.Duration
+	// SyncKind is the kind of the background resync filling the cache. The resync of a repo
+	// is stopped when its configs are evicted, so that it does not keep evicted repos alive.
+	SyncKind string
+}
+
+func NewConfigCache[T any](opts ...ConfigCacheOptions) ConfigCache[T] {
+	o := mergeConfigCacheOptions(opts)
+	items := newLRUCache[configCacheItem[T]](o.Size, o.TTL)
+	if o.SyncKind != "" {
+		items.onEvict = func(key string, _ configCacheItem[T]) {
+			// Keys are owner/repo/path
+			parts := strings.SplitN(key, "/", 3)
+			if len(parts) == 3 {
+				syncer.CacheSyncer.Remove(syncer.CacheSyncFuncKey{Owner: parts[0], Repo: parts[1], Kind: o.SyncKind})
+			}
+		}
+	}
+	return &configCache[T]{items: items}
 }
 
 type configCache[T any] struct {
-	sync.Map
+	items *lruCache[configCacheItem[T]]
+}
+
+type configCacheItem[T any] struct {
+	cfg  *T
+	sha  string
+	etag string
 }
 
 func (c *configCache[T]) Get(owner, repo, path string) *T {
 	key := c.key(owner, repo, path)
-	v, ok := c.Map.Load(key)
+	v, ok := c.items.Get(key)
 	if !ok {
 		return nil
 	}
-	return v.(*T)
+	return v.cfg
+}
+
+func (c *configCache[T]) Has(owner, repo, path string) bool {
+	return c.Get(owner, repo, path) != nil
 }
 
 func (c *configCache[T]) Save(owner, repo, path string, cfg *T) {
+	c.SaveWithSHA(owner, repo, path, "", cfg)
+}
+
+func (c *configCache[T]) SaveWithSHA(owner, repo, path, sha string, cfg *T) {
+	c.SaveWithETag(owner, repo, path, sha, "", cfg)
+}
+
+func (c *configCache[T]) SaveWithETag(owner, repo, path, sha, etag string, cfg *T) {
 	key := c.key(owner, repo, path)
-	c.Map.Store(key, cfg)
+	c.items.Add(key, configCacheItem[T]{cfg: cfg, sha: sha, etag: etag})
+}
+
+func (c *configCache[T]) ETag(owner, repo, path string) string {
+	v, ok := c.items.Get(c.key(owner, repo, path))
+	if !ok {
+		return ""
+	}
+	return v.etag
+}
+
+func (c *configCache[T]) Touch(owner, repo, path string) {
+	c.items.Touch(c.key(owner, repo, path))
+}
+
+func (c *configCache[T]) Delete(owner, repo, path string) {
+	c.items.Remove(c.key(owner, repo, path))
+}
+
+func (c *configCache[T]) DeleteRepo(owner, repo string) {
+	prefix := c.key(owner, repo, "")
+	c.items.RemoveFunc(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+func (c *configCache[T]) Entries() []ConfigCacheEntry {
+	var entries []ConfigCacheEntry
+	c.items.Range(func(key string, value configCacheItem[T], savedAt time.Time) {
+		entries = append(entries, newConfigCacheEntry(key, value.sha, savedAt))
+	})
+	return entries
 }
 
 func (c *configCache[T]) key(owner, repo, path string) string {
 	return fmt.Sprintf("%s/%s/%s", owner, repo, path)
 }
+
+// ForgetRepo drops everything cached for a repo, e.g. when the App is uninstalled from it:
+// the entries of the caches and the background resync.
+func ForgetRepo(owner, repo string, caches ...interface{ DeleteRepo(owner, repo string) }) {
+	for _, cache := range caches {
+		cache.DeleteRepo(owner, repo)
+	}
+	syncer.CacheSyncer.Forget(owner, repo)
+}
+
+func newConfigCacheEntry(key, sha string, savedAt time.Time) ConfigCacheEntry {
+	return ConfigCacheEntry{
+		Key: key, SHA: sha, SavedAt: savedAt,
+		Age: time.Since(savedAt).Truncate(time.Second).String(),
+	}
+}
+
+func mergeConfigCacheOptions(opts []ConfigCacheOptions) ConfigCacheOptions {
+	o := ConfigCacheOptions{}
+	for _, opt := range opts {
+		if opt.Size != 0 {
+			o.Size = opt.Size
+		}
+		if opt.TTL != 0 {
+			o.TTL = opt.TTL
+		}
+		if opt.SyncKind != "" {
+			o.SyncKind = opt.SyncKind
+		}
+	}
+	return o
+}
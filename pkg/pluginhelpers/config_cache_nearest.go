@@ -5,34 +5,183 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/syncer"
 )
 
-func NewConfigNearestCache[T any]() ConfigCache[T] {
-	return &nearestConfigCache[T]{
-		trie: &NearestTrie{Root: &TrieNode{Children: make(map[string]*TrieNode)}},
+// NearestConfigCache is a ConfigCache which gets the config saved at the nearest parent path.
+type NearestConfigCache[T any] interface {
+	ConfigCache[T]
+	// GetChain returns the configs saved at path and all its parent paths, nearest first.
+	GetChain(owner, repo, path string) []NearestConfig[T]
+}
+
+// NearestConfig is a config in the parent chain of a path.
+type NearestConfig[T any] struct {
+	// Path is the path the config is saved at, relative to the repo root, "" for the root.
+	Path   string
+	Config *T
+}
+
+// NewConfigNearestCache returns a cache which gets the config saved at the nearest parent path.
+// The configs of one repo are bounded and evicted together, so that a config is never
+// resolved against a partially evicted parent chain.
+func NewConfigNearestCache[T any](opts ...ConfigCacheOptions) NearestConfigCache[T] {
+	o := mergeConfigCacheOptions(opts)
+	repos := newLRUCache[*nearestRepoConfigs[T]](o.Size, o.TTL)
+	if o.SyncKind != "" {
+		repos.onEvict = func(key string, _ *nearestRepoConfigs[T]) {
+			// Keys are owner/repo
+			if owner, repo, ok := strings.Cut(key, "/"); ok {
+				syncer.CacheSyncer.Remove(syncer.CacheSyncFuncKey{Owner: owner, Repo: repo, Kind: o.SyncKind})
+			}
+		}
 	}
+	return &nearestConfigCache[T]{repos: repos}
 }
 
 type nearestConfigCache[T any] struct {
+	repos *lruCache[*nearestRepoConfigs[T]]
+}
+
+// nearestRepoConfigs contains all configs of one repo
+type nearestRepoConfigs[T any] struct {
 	sync.Map
 
 	trie *NearestTrie
 }
 
+type nearestConfigCacheItem[T any] struct {
+	configCacheItem[T]
+	savedAt time.Time
+}
+
 func (c *nearestConfigCache[T]) Get(owner, repo, path string) *T {
+	configs, ok := c.repos.Get(c.repoKey(owner, repo))
+	if !ok {
+		return nil
+	}
 	key := c.key(owner, repo, path)
-	nearestKey := c.trie.Search(key)
-	v, ok := c.Map.Load(nearestKey)
+	nearestKey := configs.trie.Search(key)
+	v, ok := configs.Map.Load(nearestKey)
+	if !ok {
+		return nil
+	}
+	return v.(nearestConfigCacheItem[T]).cfg
+}
+
+func (c *nearestConfigCache[T]) GetChain(owner, repo, path string) []NearestConfig[T] {
+	configs, ok := c.repos.Get(c.repoKey(owner, repo))
 	if !ok {
 		return nil
 	}
-	return v.(*T)
+	repoKey := c.repoKey(owner, repo)
+	var chain []NearestConfig[T]
+	for _, key := range configs.trie.SearchAll(c.key(owner, repo, path)) {
+		v, ok := configs.Map.Load(key)
+		if !ok {
+			continue
+		}
+		chain = append(chain, NearestConfig[T]{
+			Path:   strings.TrimPrefix(strings.TrimPrefix(key, repoKey), "/"),
+			Config: v.(nearestConfigCacheItem[T]).cfg,
+		})
+	}
+	return chain
+}
+
+func (c *nearestConfigCache[T]) Has(owner, repo, path string) bool {
+	configs, ok := c.repos.Get(c.repoKey(owner, repo))
+	if !ok {
+		return false
+	}
+	_, ok = configs.Map.Load(c.key(owner, repo, path))
+	return ok
 }
 
 func (c *nearestConfigCache[T]) Save(owner, repo, path string, cfg *T) {
+	c.SaveWithSHA(owner, repo, path, "", cfg)
+}
+
+func (c *nearestConfigCache[T]) SaveWithSHA(owner, repo, path, sha string, cfg *T) {
+	c.SaveWithETag(owner, repo, path, sha, "", cfg)
+}
+
+func (c *nearestConfigCache[T]) SaveWithETag(owner, repo, path, sha, etag string, cfg *T) {
+	repoKey := c.repoKey(owner, repo)
+	configs, ok := c.repos.Get(repoKey)
+	if !ok {
+		configs = &nearestRepoConfigs[T]{
+			trie: &NearestTrie{Root: &TrieNode{Children: make(map[string]*TrieNode)}},
+		}
+	}
+	// Renew the age of the repo, its configs are evicted together
+	c.repos.Add(repoKey, configs)
+	key := c.key(owner, repo, path)
+	configs.trie.Insert(key)
+	configs.Map.Store(key, nearestConfigCacheItem[T]{
+		configCacheItem: configCacheItem[T]{cfg: cfg, sha: sha, etag: etag},
+		savedAt:         time.Now(),
+	})
+}
+
+func (c *nearestConfigCache[T]) ETag(owner, repo, path string) string {
+	configs, ok := c.repos.Get(c.repoKey(owner, repo))
+	if !ok {
+		return ""
+	}
+	v, ok := configs.Map.Load(c.key(owner, repo, path))
+	if !ok {
+		return ""
+	}
+	return v.(nearestConfigCacheItem[T]).etag
+}
+
+func (c *nearestConfigCache[T]) Touch(owner, repo, path string) {
+	configs, ok := c.repos.Get(c.repoKey(owner, repo))
+	if !ok {
+		return
+	}
 	key := c.key(owner, repo, path)
-	c.trie.Insert(key)
-	c.Map.Store(key, cfg)
+	v, ok := configs.Map.Load(key)
+	if !ok {
+		return
+	}
+	item := v.(nearestConfigCacheItem[T])
+	item.savedAt = time.Now()
+	configs.Map.Store(key, item)
+	c.repos.Touch(c.repoKey(owner, repo))
+}
+
+func (c *nearestConfigCache[T]) Delete(owner, repo, path string) {
+	configs, ok := c.repos.Get(c.repoKey(owner, repo))
+	if !ok {
+		return
+	}
+	key := c.key(owner, repo, path)
+	configs.trie.Remove(key)
+	configs.Map.Delete(key)
+}
+
+func (c *nearestConfigCache[T]) DeleteRepo(owner, repo string) {
+	c.repos.Remove(c.repoKey(owner, repo))
+}
+
+func (c *nearestConfigCache[T]) Entries() []ConfigCacheEntry {
+	var entries []ConfigCacheEntry
+	c.repos.Range(func(_ string, configs *nearestRepoConfigs[T], _ time.Time) {
+		configs.Map.Range(func(key, value any) bool {
+			item := value.(nearestConfigCacheItem[T])
+			entries = append(entries, newConfigCacheEntry(key.(string), item.sha, item.savedAt))
+			return true
+		})
+	})
+	return entries
+}
+
+func (c *nearestConfigCache[T]) repoKey(owner, repo string) string {
+	return fmt.Sprintf("%s/%s", owner, repo)
 }
 
 func (c *nearestConfigCache[T]) key(owner, repo, path string) string {
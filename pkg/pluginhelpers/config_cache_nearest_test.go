@@ -72,4 +72,23 @@ var _ = Describe("ConfigNearestCache", func() {
 		})
 	})
 
+	When("Getting the parent chain", func() {
+		cache := pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration]()
+		cache.Save("foo", "bar", "", &plugins.OwnersConfiguration{Approvers: []string{"root"}})
+		cache.Save("foo", "bar", "pkg", &plugins.OwnersConfiguration{Approvers: []string{"pkg"}})
+		cache.Save("foo", "bar", "pkg/xxxx/aaaa", &plugins.OwnersConfiguration{Approvers: []string{"aaaa"}})
+		It("Should get all parent owner files, nearest first", func() {
+			chain := cache.GetChain("foo", "bar", "pkg/xxxx/aaaa/1111")
+			Expect(chain).Should(HaveLen(3))
+			Expect(chain[0].Path).Should(Equal("pkg/xxxx/aaaa"))
+			Expect(chain[1].Path).Should(Equal("pkg"))
+			Expect(chain[2].Path).Should(Equal(""))
+			Expect(chain[2].Config.Approvers).Should(Equal([]string{"root"}))
+		})
+		It("Should get root owner file only", func() {
+			chain := cache.GetChain("foo", "bar", "cmd/foo.go")
+			Expect(chain).Should(HaveLen(1))
+			Expect(chain[0].Path).Should(Equal(""))
+		})
+	})
 })
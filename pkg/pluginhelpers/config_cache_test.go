@@ -0,0 +1,99 @@
+package pluginhelpers_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/syncer"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("ConfigCache", func() {
+	When("Cache is bounded by size", func() {
+		cache := pluginhelpers.NewConfigCache[plugins.Configuration](pluginhelpers.ConfigCacheOptions{Size: 2})
+		cache.SaveWithSHA("foo", "a", "kuilei.yml", "sha-a", &plugins.Configuration{Repo: "a"})
+		cache.SaveWithSHA("foo", "b", "kuilei.yml", "sha-b", &plugins.Configuration{Repo: "b"})
+		// Use a, so that b is the least recently used one
+		cache.Get("foo", "a", "kuilei.yml")
+		cache.SaveWithSHA("foo", "c", "kuilei.yml", "sha-c", &plugins.Configuration{Repo: "c"})
+
+		It("Should evict the least recently used config", func() {
+			Expect(cache.Get("foo", "b", "kuilei.yml")).Should(BeNil())
+			Expect(cache.Get("foo", "a", "kuilei.yml")).ShouldNot(BeNil())
+			Expect(cache.Get("foo", "c", "kuilei.yml")).ShouldNot(BeNil())
+		})
+		It("Should list entries", func() {
+			entries := cache.Entries()
+			Expect(entries).Should(HaveLen(2))
+			Expect(entries[0].Key).Should(Equal("foo/c/kuilei.yml"))
+			Expect(entries[0].SHA).Should(Equal("sha-c"))
+		})
+	})
+
+	When("Configs of a synced repo are evicted", func() {
+		cache := pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](
+			pluginhelpers.ConfigCacheOptions{Size: 1, SyncKind: pluginhelpers.OwnersSyncKind},
+		)
+		key := syncer.CacheSyncFuncKey{Owner: "evict", Repo: "a", Kind: pluginhelpers.OwnersSyncKind}
+		It("Should stop the resync of the repo", func() {
+			cache.SaveWithETag("evict", "a", "", "sha-a", `"etag-a"`, &plugins.OwnersConfiguration{})
+			syncer.CacheSyncer.EnsureSync(key, func(owner, repo string) error { return nil })
+			Expect(cache.ETag("evict", "a", "")).Should(Equal(`"etag-a"`))
+
+			cache.Save("evict", "b", "", &plugins.OwnersConfiguration{})
+			Expect(cache.ETag("evict", "a", "")).Should(BeEmpty())
+			Expect(syncer.CacheSyncer.Has(key)).Should(BeFalse())
+		})
+	})
+
+	When("Cache is bounded by TTL", func() {
+		cache := pluginhelpers.NewConfigCache[plugins.Configuration](pluginhelpers.ConfigCacheOptions{TTL: 100 * time.Millisecond})
+		It("Should expire configs", func() {
+			cache.Save("foo", "a", "kuilei.yml", &plugins.Configuration{Repo: "a"})
+			Expect(cache.Has("foo", "a", "kuilei.yml")).Should(BeTrue())
+			Eventually(func() bool {
+				return cache.Has("foo", "a", "kuilei.yml")
+			}, time.Second, 50*time.Millisecond).Should(BeFalse())
+		})
+	})
+
+	When("Configs are revalidated", func() {
+		cache := pluginhelpers.NewConfigCache[plugins.Configuration](pluginhelpers.ConfigCacheOptions{TTL: 200 * time.Millisecond})
+		nearest := pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](pluginhelpers.ConfigCacheOptions{TTL: 200 * time.Millisecond})
+		It("Should renew their ages", func() {
+			cache.Save("foo", "a", "kuilei.yml", &plugins.Configuration{Repo: "a"})
+			nearest.Save("foo", "a", "", &plugins.OwnersConfiguration{})
+			nearest.Save("foo", "a", "pkg", &plugins.OwnersConfiguration{})
+			for i := 0; i < 3; i++ {
+				time.Sleep(100 * time.Millisecond)
+				cache.Touch("foo", "a", "kuilei.yml")
+				// Saving a config of the repo renews the age of all its configs
+				if i == 0 {
+					nearest.Save("foo", "a", "pkg", &plugins.OwnersConfiguration{})
+				} else {
+					nearest.Touch("foo", "a", "")
+				}
+			}
+			Expect(cache.Has("foo", "a", "kuilei.yml")).Should(BeTrue())
+			Expect(nearest.Has("foo", "a", "")).Should(BeTrue())
+			Expect(nearest.Has("foo", "a", "pkg")).Should(BeTrue())
+		})
+	})
+
+	When("Repo is removed", func() {
+		cache := pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](pluginhelpers.ConfigCacheOptions{Size: 10})
+		cache.Save("foo", "a", "", &plugins.OwnersConfiguration{Approvers: []string{"a"}})
+		cache.Save("foo", "a", "pkg", &plugins.OwnersConfiguration{Approvers: []string{"a-pkg"}})
+		cache.Save("foo", "ab", "", &plugins.OwnersConfiguration{Approvers: []string{"ab"}})
+		It("Should drop all configs of the repo", func() {
+			Expect(cache.Entries()).Should(HaveLen(3))
+			cache.DeleteRepo("foo", "a")
+			Expect(cache.Get("foo", "a", "pkg/foo.go")).Should(BeNil())
+			Expect(cache.Get("foo", "ab", "pkg/foo.go")).ShouldNot(BeNil())
+			Expect(cache.Entries()).Should(HaveLen(1))
+		})
+	})
+})
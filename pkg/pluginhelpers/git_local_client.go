@@ -0,0 +1,197 @@
+package pluginhelpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/airconduct/go-probot"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// Committer of the commits created by the local git client.
+const (
+	gitCommitterName  = "kuilei[bot]"
+	gitCommitterEmail = "kuilei[bot]@users.noreply.github.com"
+)
+
+// GitRemote is the remote of a repo. AuthHeader is passed to git by http.extraHeader in the
+// environment, so that credentials never show up in the remote URL, the arguments or the output of git.
+type GitRemote struct {
+	URL        string
+	AuthHeader string
+}
+
+// GitLocalClientFromGithub returns a local git client which clones repos from GitHub with
+// the token of the GitHub client.
+func GitLocalClientFromGithub(gh *probot.GitHubClient) plugins.GitLocalClient {
+	return GitLocalClientFromRemote(func(ctx context.Context, repo plugins.GitRepo) (GitRemote, error) {
+		token, err := githubToken(ctx, gh)
+		if err != nil {
+			return GitRemote{}, err
+		}
+		host := gh.BaseURL.Host
+		if host == "api.github.com" {
+			host = "github.com"
+		}
+		u := &url.URL{
+			Scheme: gh.BaseURL.Scheme,
+			Host:   host,
+			Path:   fmt.Sprintf("/%s/%s.git", repo.Owner.Name, repo.Name),
+		}
+		basic := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		return GitRemote{URL: u.String(), AuthHeader: "Authorization: Basic " + basic}, nil
+	})
+}
+
+// GitLocalClientFromRemote returns a local git client which clones repos from the remote returned
+// by getRemote, whose URL can also be the path of a local bare repo.
+func GitLocalClientFromRemote(getRemote func(ctx context.Context, repo plugins.GitRepo) (GitRemote, error)) plugins.GitLocalClient {
+	return &gitLocalClient{getRemote: getRemote}
+}
+
+type gitLocalClient struct {
+	getRemote func(ctx context.Context, repo plugins.GitRepo) (GitRemote, error)
+}
+
+func (c *gitLocalClient) CherryPick(ctx context.Context, repo plugins.GitRepo, opts plugins.GitCherryPickOptions) ([]string, error) {
+	remote, err := c.getRemote(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "kuilei-git-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	var env []string
+	if remote.AuthHeader != "" {
+		env = []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=" + remote.AuthHeader}
+	}
+	git := func(args ...string) (string, error) {
+		return runGit(ctx, dir, env, args...)
+	}
+
+	if _, err := git("init", "--quiet"); err != nil {
+		return nil, err
+	}
+	if _, err := git("remote", "add", "origin", remote.URL); err != nil {
+		return nil, err
+	}
+	fetch := append([]string{"fetch", "--quiet", "--no-tags", "origin", fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", opts.Onto, opts.Onto)}, opts.Refs...)
+	if _, err := git(fetch...); err != nil {
+		return nil, err
+	}
+	if _, err := git("checkout", "--quiet", "-b", opts.Branch, "origin/"+opts.Onto); err != nil {
+		return nil, err
+	}
+	picks, err := commitsToPick(git, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, pick := range picks {
+		if _, err := git(append([]string{"cherry-pick"}, pick...)...); err == nil {
+			continue
+		}
+		unmerged, diffErr := git("diff", "--name-only", "--diff-filter=U")
+		if diffErr != nil {
+			return nil, diffErr
+		}
+		if conflicts := strings.Fields(unmerged); len(conflicts) > 0 {
+			return conflicts, nil
+		}
+		// The commit is already applied
+		if _, err := git("cherry-pick", "--skip"); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := git("push", "--quiet", "origin", "HEAD:refs/heads/"+opts.Branch); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// commitsToPick returns the cherry-pick arguments of each commit to apply. A merge commit is
+// picked against its first parent. Otherwise the pull request is squashed or rebased, and the
+// rebased commits are told apart by the message of the last one, which is kept by a rebase.
+func commitsToPick(git func(args ...string) (string, error), opts plugins.GitCherryPickOptions) ([][]string, error) {
+	parents, err := git("rev-list", "--parents", "-n", "1", opts.MergeSHA)
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.Fields(parents)) > 2 {
+		return [][]string{{"-m", "1", opts.MergeSHA}}, nil
+	}
+	// The commits of the pull request, not including the ones merged from the base branch
+	own, err := git("rev-list", "--count", "--no-merges", opts.HeadSHA, "--not", opts.MergeSHA)
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(own))
+	if err != nil {
+		return nil, err
+	}
+	if count > 1 {
+		mergeMessage, err := git("log", "-1", "--format=%B", opts.MergeSHA)
+		if err != nil {
+			return nil, err
+		}
+		headMessage, err := git("log", "-1", "--format=%B", opts.HeadSHA)
+		if err != nil {
+			return nil, err
+		}
+		if mergeMessage == headMessage {
+			out, err := git("rev-list", "--reverse", fmt.Sprintf("%s~%d..%s", opts.MergeSHA, count, opts.MergeSHA))
+			if err != nil {
+				return nil, err
+			}
+			var picks [][]string
+			for _, commit := range strings.Fields(out) {
+				picks = append(picks, []string{commit})
+			}
+			return picks, nil
+		}
+	}
+	return [][]string{{opts.MergeSHA}}, nil
+}
+
+// runGit runs git in dir with the extra environment variables, the error contains the output of git.
+func runGit(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{
+		"-c", "user.name=" + gitCommitterName, "-c", "user.email=" + gitCommitterEmail,
+	}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// githubToken returns the token the GitHub client authenticates with, either the token of the
+// App installation or a static token.
+func githubToken(ctx context.Context, gh *probot.GitHubClient) (string, error) {
+	switch tr := gh.Client().Transport.(type) {
+	case interface {
+		Token(context.Context) (string, error)
+	}:
+		return tr.Token(ctx)
+	case *oauth2.Transport:
+		token, err := tr.Source.Token()
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("unsupported transport of GitHub client")
+}
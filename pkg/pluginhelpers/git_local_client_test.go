@@ -0,0 +1,128 @@
+package pluginhelpers_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("GitLocalClient", func() {
+	var remote, work string
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = work
+		out, err := cmd.CombinedOutput()
+		Expect(err).Should(Succeed(), string(out))
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(file, content string) string {
+		Expect(os.WriteFile(filepath.Join(work, file), []byte(content), 0644)).Should(Succeed())
+		git("add", file)
+		git("commit", "--quiet", "-m", "update "+file)
+		return git("rev-parse", "HEAD")
+	}
+	client := pluginhelpers.GitLocalClientFromRemote(func(ctx context.Context, repo plugins.GitRepo) (pluginhelpers.GitRemote, error) {
+		return pluginhelpers.GitRemote{URL: remote}, nil
+	})
+	cherryPick := func(mergeSHA, headSHA string) []string {
+		conflicts, err := client.CherryPick(context.TODO(), plugins.GitRepo{}, plugins.GitCherryPickOptions{
+			Onto: "release", Refs: []string{"refs/heads/main", "refs/heads/feature"},
+			MergeSHA: mergeSHA, HeadSHA: headSHA, Branch: "cherry-pick-1-to-release",
+		})
+		Expect(err).Should(Succeed())
+		return conflicts
+	}
+	picked := func(file string) string {
+		git("fetch", "--quiet", remote, "cherry-pick-1-to-release")
+		return git("show", "FETCH_HEAD:"+file)
+	}
+	exists := func(file string) bool {
+		return exec.Command("git", "--git-dir", remote, "cat-file", "-e", "cherry-pick-1-to-release:"+file).Run() == nil
+	}
+
+	var headSHA string
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+		root := GinkgoT().TempDir()
+		remote, work = filepath.Join(root, "remote.git"), filepath.Join(root, "work")
+		Expect(exec.Command("git", "init", "--quiet", "--bare", remote).Run()).Should(Succeed())
+		Expect(os.Mkdir(work, 0755)).Should(Succeed())
+		git("init", "--quiet")
+		git("checkout", "--quiet", "-b", "main")
+		commit("a.txt", "a\n")
+		git("branch", "release")
+		git("checkout", "--quiet", "-b", "feature")
+		commit("b.txt", "b\n")
+		headSHA = commit("a.txt", "a\nfeature\n")
+		git("checkout", "--quiet", "main")
+	})
+	push := func() {
+		git("push", "--quiet", remote, "main", "release", "feature")
+	}
+
+	It("Should cherry-pick a merge commit", func() {
+		git("merge", "--quiet", "--no-ff", "-m", "Merge feature", "feature")
+		push()
+		Expect(cherryPick(git("rev-parse", "HEAD"), headSHA)).Should(BeEmpty())
+		Expect(picked("a.txt")).Should(Equal("a\nfeature"))
+		Expect(picked("b.txt")).Should(Equal("b"))
+		Expect(git("rev-list", "--count", "FETCH_HEAD")).Should(Equal("2"))
+	})
+
+	It("Should leave out the commits of a pull request merged from the base branch", func() {
+		commit("c.txt", "c\n")
+		git("checkout", "--quiet", "feature")
+		git("merge", "--quiet", "--no-ff", "-m", "Merge main into feature", "main")
+		headSHA = commit("d.txt", "d\n")
+		git("checkout", "--quiet", "main")
+		git("merge", "--quiet", "--no-ff", "-m", "Merge feature", "feature")
+		push()
+		Expect(cherryPick(git("rev-parse", "HEAD"), headSHA)).Should(BeEmpty())
+		Expect(picked("a.txt")).Should(Equal("a\nfeature"))
+		Expect(picked("d.txt")).Should(Equal("d"))
+		Expect(exists("b.txt")).Should(BeTrue())
+		Expect(exists("c.txt")).Should(BeFalse())
+	})
+
+	It("Should cherry-pick a squashed commit", func() {
+		commit("c.txt", "c\n")
+		git("merge", "--quiet", "--squash", "feature")
+		git("commit", "--quiet", "-m", "Feature (#1)")
+		push()
+		Expect(cherryPick(git("rev-parse", "HEAD"), headSHA)).Should(BeEmpty())
+		Expect(picked("a.txt")).Should(Equal("a\nfeature"))
+		Expect(exists("c.txt")).Should(BeFalse())
+		Expect(git("rev-list", "--count", "FETCH_HEAD")).Should(Equal("2"))
+	})
+
+	It("Should cherry-pick rebased commits", func() {
+		commit("c.txt", "c\n")
+		git("cherry-pick", "main..feature")
+		push()
+		Expect(cherryPick(git("rev-parse", "HEAD"), headSHA)).Should(BeEmpty())
+		Expect(picked("a.txt")).Should(Equal("a\nfeature"))
+		Expect(picked("b.txt")).Should(Equal("b"))
+		Expect(exists("c.txt")).Should(BeFalse())
+		Expect(git("rev-list", "--count", "FETCH_HEAD")).Should(Equal("3"))
+	})
+
+	It("Should report conflicting files", func() {
+		git("merge", "--quiet", "--no-ff", "-m", "Merge feature", "feature")
+		mergeSHA := git("rev-parse", "HEAD")
+		git("checkout", "--quiet", "release")
+		commit("a.txt", "a\nrelease\n")
+		push()
+		Expect(cherryPick(mergeSHA, headSHA)).Should(Equal([]string{"a.txt"}))
+		Expect(exec.Command("git", "--git-dir", remote, "rev-parse", "--verify", "--quiet", "cherry-pick-1-to-release").Run()).ShouldNot(Succeed())
+	})
+})
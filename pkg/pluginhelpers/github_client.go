@@ -2,6 +2,8 @@ package pluginhelpers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/airconduct/go-probot"
@@ -21,6 +23,10 @@ func GitRepoClientFromGithub(gh *probot.GitHubClient) plugins.GitRepoClient {
 	return &githubClientWrapper{ghClient: gh}
 }
 
+func GitOrgClientFromGithub(gh *probot.GitHubClient) plugins.GitOrgClient {
+	return &githubClientWrapper{ghClient: gh}
+}
+
 type githubClientWrapper struct {
 	ghClient *probot.GitHubClient
 }
@@ -32,6 +38,18 @@ func (c *githubClientWrapper) CreateIssueComment(ctx context.Context, repo plugi
 	return err
 }
 
+func (c *githubClientWrapper) EditIssueComment(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, in plugins.GitIssueComment) error {
+	_, _, err := c.ghClient.Issues.EditComment(ctx, repo.Owner.Name, repo.Name, int64(in.ID), &github.IssueComment{
+		Body: github.String(in.Body),
+	})
+	return err
+}
+
+func (c *githubClientWrapper) DeleteIssueComment(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, in plugins.GitIssueComment) error {
+	_, err := c.ghClient.Issues.DeleteComment(ctx, repo.Owner.Name, repo.Name, int64(in.ID))
+	return err
+}
+
 func (c *githubClientWrapper) AddLabel(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
 	var labelNames []string
 	for _, l := range labels {
@@ -43,19 +61,140 @@ func (c *githubClientWrapper) AddLabel(ctx context.Context, repo plugins.GitRepo
 
 func (c *githubClientWrapper) RemoveLabel(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
 	_, err := c.ghClient.Issues.RemoveLabelForIssue(ctx, repo.Owner.Name, repo.Name, issue.Number, label.Name)
+	// The label is not on the issue
+	if isNotFound(err) {
+		return nil
+	}
 	return err
 }
 
-func (c *githubClientWrapper) ListFiles(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
-	files, _, err := c.ghClient.PullRequests.ListFiles(ctx, repo.Owner.Name, repo.Name, pr.Number, &github.ListOptions{})
-	if err != nil {
-		return nil, err
+func (c *githubClientWrapper) ListIssueComments(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) ([]plugins.GitIssueComment, error) {
+	var out []plugins.GitIssueComment
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := c.ghClient.Issues.ListComments(ctx, repo.Owner.Name, repo.Name, issue.Number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			out = append(out, plugins.GitIssueComment{
+				ID:   int(comment.GetID()),
+				Body: comment.GetBody(),
+				User: GitUserFromGithub(comment.User),
+				URL:  comment.GetHTMLURL(),
+
+				AuthorAssociation: comment.GetAuthorAssociation(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *githubClientWrapper) AddAssignees(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, logins []string) error {
+	_, _, err := c.ghClient.Issues.AddAssignees(ctx, repo.Owner.Name, repo.Name, issue.Number, logins)
+	return err
+}
+
+func (c *githubClientWrapper) RemoveAssignees(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, logins []string) error {
+	_, _, err := c.ghClient.Issues.RemoveAssignees(ctx, repo.Owner.Name, repo.Name, issue.Number, logins)
+	return err
+}
+
+func (c *githubClientWrapper) CloseIssue(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, reason plugins.GitIssueCloseReason) error {
+	req := &github.IssueRequest{State: github.String("closed")}
+	if reason != "" {
+		req.StateReason = github.String(reason)
+	}
+	_, _, err := c.ghClient.Issues.Edit(ctx, repo.Owner.Name, repo.Name, issue.Number, req)
+	return err
+}
+
+func (c *githubClientWrapper) ReopenIssue(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) error {
+	_, _, err := c.ghClient.Issues.Edit(ctx, repo.Owner.Name, repo.Name, issue.Number, &github.IssueRequest{
+		State: github.String("open"),
+	})
+	return err
+}
+
+func (c *githubClientWrapper) ListMilestones(ctx context.Context, repo plugins.GitRepo) ([]plugins.GitMilestone, error) {
+	var out []plugins.GitMilestone
+	opts := &github.MilestoneListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		milestones, resp, err := c.ghClient.Issues.ListMilestones(ctx, repo.Owner.Name, repo.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range milestones {
+			out = append(out, plugins.GitMilestone{Number: m.GetNumber(), Title: m.GetTitle(), State: m.GetState()})
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
 	}
+}
+
+func (c *githubClientWrapper) SetMilestone(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, milestone int) error {
+	if milestone == 0 {
+		_, _, err := c.ghClient.Issues.RemoveMilestone(ctx, repo.Owner.Name, repo.Name, issue.Number)
+		return err
+	}
+	_, _, err := c.ghClient.Issues.Edit(ctx, repo.Owner.Name, repo.Name, issue.Number, &github.IssueRequest{
+		Milestone: github.Int(milestone),
+	})
+	return err
+}
+
+func (c *githubClientWrapper) ListFiles(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
 	var commitFiles []plugins.GitCommitFile
-	for _, f := range files {
-		commitFiles = append(commitFiles, plugins.GitCommitFile{Path: f.GetFilename()})
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := c.ghClient.PullRequests.ListFiles(ctx, repo.Owner.Name, repo.Name, pr.Number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			commitFiles = append(commitFiles, plugins.GitCommitFile{
+				Path:      f.GetFilename(),
+				Status:    f.GetStatus(),
+				Changes:   f.GetChanges(),
+				Additions: f.GetAdditions(),
+				Deletions: f.GetDeletions(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return commitFiles, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *githubClientWrapper) ListCommits(ctx context.Context, repo plugins.GitRepo, number int) ([]plugins.GitCommit, error) {
+	var out []plugins.GitCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		commits, resp, err := c.ghClient.PullRequests.ListCommits(ctx, repo.Owner.Name, repo.Name, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			out = append(out, plugins.GitCommit{
+				Sha:     commit.GetSHA(),
+				Message: commit.GetCommit().GetMessage(),
+				Author: plugins.GitCommitAuthor{
+					Name:  commit.GetCommit().GetAuthor().GetName(),
+					Email: commit.GetCommit().GetAuthor().GetEmail(),
+				},
+			})
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
 	}
-	return commitFiles, nil
 }
 
 func (c *githubClientWrapper) GetPR(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
@@ -73,14 +212,36 @@ func (c *githubClientWrapper) GetPR(ctx context.Context, repo plugins.GitRepo, n
 		Labels:    GitLabelsFromGithub(pr.Labels),
 		Assignees: GitUsersFromGithub(pr.Assignees),
 		User:      GitUserFromGithub(pr.User),
+
+		RequestedReviewers: GitUsersFromGithub(pr.RequestedReviewers),
+		Merged:             pr.GetMerged(),
+		MergeSHA:           pr.GetMergeCommitSHA(),
+		Draft:              pr.GetDraft(),
+		Mergeable:          gitMergeableFromGithub(pr.Mergeable),
 		// Add head
 		Head: plugins.GitBranch{
 			SHA: pr.Head.GetSHA(),
 			Ref: pr.Head.GetRef(),
 		},
+		Base: plugins.GitBranch{
+			SHA: pr.Base.GetSHA(),
+			Ref: pr.Base.GetRef(),
+		},
 	}, nil
 }
 
+// gitMergeableFromGithub converts the mergeable field of a pull request, which is null
+// while GitHub is still calculating it.
+func gitMergeableFromGithub(mergeable *bool) plugins.GitMergeableState {
+	switch {
+	case mergeable == nil:
+		return plugins.GitMergeableStateUnknown
+	case *mergeable:
+		return plugins.GitMergeableStateMergeable
+	}
+	return plugins.GitMergeableStateConflicting
+}
+
 func (c *githubClientWrapper) MergePR(ctx context.Context, repo plugins.GitRepo, number int, method string) error {
 	_, _, err := c.ghClient.PullRequests.Merge(ctx, repo.Owner.Name, repo.Name, number, "", &github.PullRequestOptions{
 		MergeMethod: method,
@@ -88,6 +249,33 @@ func (c *githubClientWrapper) MergePR(ctx context.Context, repo plugins.GitRepo,
 	return err
 }
 
+func (c *githubClientWrapper) RequestReviewers(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+	_, _, err := c.ghClient.PullRequests.RequestReviewers(ctx, repo.Owner.Name, repo.Name, number, github.ReviewersRequest{
+		Reviewers: reviewers,
+	})
+	return err
+}
+
+func (c *githubClientWrapper) RemoveReviewers(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+	_, err := c.ghClient.PullRequests.RemoveReviewers(ctx, repo.Owner.Name, repo.Name, number, github.ReviewersRequest{
+		Reviewers: reviewers,
+	})
+	return err
+}
+
+func (c *githubClientWrapper) CreatePR(ctx context.Context, repo plugins.GitRepo, in plugins.GitPullRequest) (int, error) {
+	pr, _, err := c.ghClient.PullRequests.Create(ctx, repo.Owner.Name, repo.Name, &github.NewPullRequest{
+		Title: github.String(in.Title),
+		Body:  github.String(in.Body),
+		Head:  github.String(in.Head.Ref),
+		Base:  github.String(in.Base.Ref),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pr.GetNumber(), nil
+}
+
 func (c *githubClientWrapper) CreateStatus(
 	ctx context.Context, repo plugins.GitRepo, ref string, status plugins.GitCommitStatus,
 ) error {
@@ -117,20 +305,111 @@ func (c *githubClientWrapper) ListStatuses(ctx context.Context, repo plugins.Git
 	return out, nil
 }
 
+// ListChecks lists all check runs of ref.
 func (c *githubClientWrapper) ListChecks(ctx context.Context, repo plugins.GitRepo, ref string) ([]plugins.GitCommitCheck, error) {
-	resutls, _, err := c.ghClient.Checks.ListCheckRunsForRef(
-		ctx, repo.Owner.Name, repo.Name, ref, &github.ListCheckRunsOptions{},
-	)
+	var out []plugins.GitCommitCheck
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		results, resp, err := c.ghClient.Checks.ListCheckRunsForRef(ctx, repo.Owner.Name, repo.Name, ref, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, check := range results.CheckRuns {
+			out = append(out, plugins.GitCommitCheck{
+				ID:         check.GetID(),
+				SuiteID:    check.GetCheckSuite().GetID(),
+				Name:       check.GetName(),
+				Status:     strings.ToUpper(check.GetStatus()),
+				Conclusion: strings.ToUpper(check.GetConclusion()),
+			})
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *githubClientWrapper) IsCollaborator(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+	ok, _, err := c.ghClient.Repositories.IsCollaborator(ctx, repo.Owner.Name, repo.Name, login)
+	return ok, err
+}
+
+// IsTeamMember uses the cached team members, which are also used to expand teams in OWNERS files.
+func (c *githubClientWrapper) IsTeamMember(ctx context.Context, org, team, login string) (bool, error) {
+	members, err := listTeamMembers(ctx, c.ghClient, org, team)
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if strings.EqualFold(member, login) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *githubClientWrapper) IsOrgMember(ctx context.Context, org, login string) (bool, error) {
+	ok, _, err := c.ghClient.Organizations.IsMember(ctx, org, login)
+	if isNotFound(err) {
+		return false, nil
+	}
+	return ok, err
+}
+
+func (c *githubClientWrapper) RerequestCheckSuite(ctx context.Context, repo plugins.GitRepo, suiteID int64) error {
+	_, err := c.ghClient.Checks.ReRequestCheckSuite(ctx, repo.Owner.Name, repo.Name, suiteID)
+	return err
+}
+
+func (c *githubClientWrapper) RerequestCheckRun(ctx context.Context, repo plugins.GitRepo, runID int64) error {
+	_, err := c.ghClient.Checks.ReRequestCheckRun(ctx, repo.Owner.Name, repo.Name, runID)
+	return err
+}
+
+func (c *githubClientWrapper) ApproveWorkflowRuns(ctx context.Context, repo plugins.GitRepo, headSHA string) error {
+	opts := &github.ListWorkflowRunsOptions{Status: "action_required", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := c.ghClient.Actions.ListRepositoryWorkflowRuns(ctx, repo.Owner.Name, repo.Name, opts)
+		if err != nil {
+			return err
+		}
+		for _, run := range runs.WorkflowRuns {
+			if run.GetHeadSHA() != headSHA {
+				continue
+			}
+			// go-github does not support approving workflow runs yet
+			req, err := c.ghClient.NewRequest(
+				http.MethodPost, fmt.Sprintf("repos/%s/%s/actions/runs/%d/approve", repo.Owner.Name, repo.Name, run.GetID()), nil,
+			)
+			if err != nil {
+				return err
+			}
+			if _, err := c.ghClient.Do(ctx, req, nil); err != nil {
+				return err
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *githubClientWrapper) GetFile(ctx context.Context, repo plugins.GitRepo, ref, path string) ([]byte, error) {
+	file, _, _, err := c.ghClient.Repositories.GetContents(ctx, repo.Owner.Name, repo.Name, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if isNotFound(err) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	var out []plugins.GitCommitCheck
-	for _, check := range resutls.CheckRuns {
-		out = append(out, plugins.GitCommitCheck{
-			Name:       check.GetName(),
-			Status:     strings.ToUpper(check.GetStatus()),
-			Conclusion: strings.ToUpper(check.GetConclusion()),
-		})
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", path)
 	}
-	return out, nil
+	contents, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(contents), nil
 }
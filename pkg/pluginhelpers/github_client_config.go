@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/go-github/v48/github"
 	"sigs.k8s.io/yaml"
 
 	"github.com/airconduct/go-probot"
@@ -12,6 +11,9 @@ import (
 	"github.com/airconduct/kuilei/pkg/plugins"
 )
 
+// PluginConfigSyncKind is the kind of the background resync of plugin configs.
+const PluginConfigSyncKind = "plugin-config"
+
 func PluginConfigClientFromGithub(gh *probot.GitHubClient, configPath string, cache ConfigCache[plugins.Configuration]) plugins.PluginConfigClient {
 	c := &githubPluginConfigClient{
 		ghClient: gh, configPath: configPath,
@@ -37,7 +39,7 @@ func (c *githubPluginConfigClient) GetConfig(owner, repo string) (plugins.Config
 
 		}
 		syncer.CacheSyncer.EnsureSync(syncer.CacheSyncFuncKey{
-			Owner: owner, Repo: repo, Kind: "plugin-config",
+			Owner: owner, Repo: repo, Kind: PluginConfigSyncKind,
 		}, c.syncConfigFromRemote)
 		cfg = c.getConfigFromCache(owner, repo)
 		if cfg == nil {
@@ -55,19 +57,24 @@ func (c *githubPluginConfigClient) syncConfigFromRemote(owner, repo string) erro
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	file, _, _, err := c.ghClient.Repositories.GetContents(ctx, owner, repo, c.configPath, &github.RepositoryContentGetOptions{})
+	etag := c.configCache.ETag(owner, repo, c.configPath)
+	contents, sha, etag, modified, err := getContentsIfModified(ctx, c.ghClient, owner, repo, c.configPath, etag)
 	if err != nil {
+		if isNotFound(err) {
+			// The config file has been removed, do not keep a stale config
+			c.configCache.Delete(owner, repo, c.configPath)
+		}
 		return err
 	}
-	contents, err := file.GetContent()
-	if err != nil {
-		return err
+	if !modified {
+		c.configCache.Touch(owner, repo, c.configPath)
+		return nil
 	}
 
 	cfg := new(plugins.Configuration)
 	if err := yaml.Unmarshal([]byte(contents), cfg); err != nil {
 		return err
 	}
-	c.configCache.Save(owner, repo, c.configPath, cfg)
+	c.configCache.SaveWithETag(owner, repo, c.configPath, sha, etag, cfg)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v48/github"
@@ -14,11 +15,22 @@ import (
 	"github.com/airconduct/kuilei/pkg/plugins"
 )
 
-func OwnersClientFromGithub(gh *probot.GitHubClient, ownersFileName string, cache ConfigCache[plugins.OwnersConfiguration]) plugins.OwnersClient {
+const (
+	// OwnersAliasesFileName is the name of the OWNERS_ALIASES file at the repo root.
+	OwnersAliasesFileName = "OWNERS_ALIASES"
+	// OwnersSyncKind is the kind of the background resync of OWNERS files.
+	OwnersSyncKind = "owners"
+)
+
+func OwnersClientFromGithub(
+	gh *probot.GitHubClient, ownersFileName string,
+	cache NearestConfigCache[plugins.OwnersConfiguration], aliasesCache ConfigCache[plugins.OwnersAliases],
+) plugins.OwnersClient {
 	c := &githubOwnersClient{
 		ghClient:       gh,
 		ownersFileName: ownersFileName,
 		configCache:    cache,
+		aliasesCache:   aliasesCache,
 	}
 
 	return c
@@ -28,24 +40,43 @@ type githubOwnersClient struct {
 	ghClient       *probot.GitHubClient
 	ownersFileName string
 
-	configCache ConfigCache[plugins.OwnersConfiguration]
+	configCache  NearestConfigCache[plugins.OwnersConfiguration]
+	aliasesCache ConfigCache[plugins.OwnersAliases]
 }
 
+// GetOwners returns the owners of file combined from all OWNERS files in its parent chain.
+// Aliases of OWNERS_ALIASES and "@org/team-slug" entries are expanded to logins.
 func (c *githubOwnersClient) GetOwners(owner, repo, file string) (plugins.OwnersConfiguration, error) {
-	cfg := c.configCache.Get(owner, repo, file)
-	if cfg == nil {
+	chain := c.configCache.GetChain(owner, repo, file)
+	if len(chain) == 0 {
 		if err := c.syncOwnersFromRemote(owner, repo); err != nil {
 			return plugins.OwnersConfiguration{}, err
 		}
 		syncer.CacheSyncer.EnsureSync(syncer.CacheSyncFuncKey{
-			Owner: owner, Repo: repo, Kind: "owners",
+			Owner: owner, Repo: repo, Kind: OwnersSyncKind,
 		}, c.syncOwnersFromRemote)
-		cfg = c.configCache.Get(owner, repo, file)
-		if cfg == nil {
-			return plugins.OwnersConfiguration{}, nil
-		}
+		chain = c.configCache.GetChain(owner, repo, file)
+	}
+	var items []plugins.OwnersChainItem
+	for _, item := range chain {
+		items = append(items, plugins.OwnersChainItem{Dir: item.Path, Config: item.Config})
+	}
+	cfg := plugins.ResolveOwners(file, items)
+	cfg.Owner, cfg.Repo = owner, repo
+	c.expandOwners(owner, repo, &cfg)
+	return cfg, nil
+}
+
+func (c *githubOwnersClient) expandOwners(owner, repo string, cfg *plugins.OwnersConfiguration) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	aliases := plugins.OwnersAliases{}
+	if a := c.aliasesCache.Get(owner, repo, OwnersAliasesFileName); a != nil {
+		aliases = *a
+	}
+	for _, logins := range []*[]string{&cfg.Reviewers, &cfg.Approvers, &cfg.EmeritusApprovers, &cfg.RequiredReviewers} {
+		*logins = expandTeams(ctx, c.ghClient, aliases.Expand(*logins))
 	}
-	return *cfg, nil
 }
 
 func (c *githubOwnersClient) syncOwnersFromRemote(owner, repo string) error {
@@ -57,20 +88,60 @@ func (c *githubOwnersClient) syncOwnersFromRemote(owner, repo string) error {
 		return err
 	}
 	for _, r := range result.CodeResults {
-		file, _, _, err := c.ghClient.Repositories.GetContents(ctx, owner, repo, r.GetPath(), &github.RepositoryContentGetOptions{})
-		if err != nil {
-			return err
-		}
-		contents, err := file.GetContent()
-		if err != nil {
+		if err := c.syncOwnersFileFromRemote(ctx, owner, repo, r.GetPath()); err != nil {
 			return err
 		}
-		cfg := &plugins.OwnersConfiguration{}
-		if err := yaml.Unmarshal([]byte(contents), cfg); err != nil {
-			return err
+	}
+	return c.syncOwnersAliasesFromRemote(ctx, owner, repo)
+}
+
+// syncOwnersAliasesFromRemote refetches the OWNERS_ALIASES file, it is removed from the cache
+// if the file does not exist.
+func (c *githubOwnersClient) syncOwnersAliasesFromRemote(ctx context.Context, owner, repo string) error {
+	etag := c.aliasesCache.ETag(owner, repo, OwnersAliasesFileName)
+	contents, sha, etag, modified, err := getContentsIfModified(ctx, c.ghClient, owner, repo, OwnersAliasesFileName, etag)
+	if err != nil {
+		if isNotFound(err) {
+			c.aliasesCache.Delete(owner, repo, OwnersAliasesFileName)
+			return nil
 		}
+		return err
+	}
+	if !modified {
+		c.aliasesCache.Touch(owner, repo, OwnersAliasesFileName)
+		return nil
+	}
+	aliases := &plugins.OwnersAliases{}
+	if err := yaml.Unmarshal([]byte(contents), aliases); err != nil {
+		return err
+	}
+	c.aliasesCache.SaveWithETag(owner, repo, OwnersAliasesFileName, sha, etag, aliases)
+	return nil
+}
 
-		c.configCache.Save(owner, repo, filepath.Dir(r.GetPath()), cfg)
+// syncOwnersFileFromRemote refetches a single OWNERS file, it is removed from the cache
+// if the file does not exist anymore.
+func (c *githubOwnersClient) syncOwnersFileFromRemote(ctx context.Context, owner, repo, file string) error {
+	dir := filepath.Dir(file)
+	etag := c.configCache.ETag(owner, repo, dir)
+	contents, sha, etag, modified, err := getContentsIfModified(ctx, c.ghClient, owner, repo, file, etag)
+	if err != nil {
+		if isNotFound(err) {
+			c.configCache.Delete(owner, repo, dir)
+			return nil
+		}
+		return err
+	}
+	if !modified {
+		c.configCache.Touch(owner, repo, dir)
+		return nil
 	}
+	cfg := &plugins.OwnersConfiguration{}
+	if err := yaml.Unmarshal([]byte(contents), cfg); err != nil {
+		return err
+	}
+	cfg.Owner, cfg.Repo, cfg.Path = owner, repo, strings.TrimLeft(filepath.Clean("/"+dir), "/")
+
+	c.configCache.SaveWithETag(owner, repo, dir, sha, etag, cfg)
 	return nil
 }
@@ -0,0 +1,89 @@
+package pluginhelpers_test
+
+import (
+	"github.com/google/go-github/v48/github"
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("OwnersClientFromGithub", func() {
+	BeforeEach(func() {
+		gock.DisableNetworking()
+	})
+	AfterEach(func() {
+		gock.Off()
+	})
+
+	It("Should expand aliases and teams", func() {
+		gock.New("https://api.github.com").
+			Get("/search/code").
+			Reply(200).
+			JSON(map[string]interface{}{"items": []map[string]string{{"path": "OWNERS"}, {"path": "pkg/OWNERS"}}})
+		gock.New("https://api.github.com").
+			Get("/repos/aliases-owner/aliases-repo/contents/OWNERS").
+			Reply(200).
+			JSON(fakeContents("approvers:\n- sig-root\n"))
+		gock.New("https://api.github.com").
+			Get("/repos/aliases-owner/aliases-repo/contents/pkg/OWNERS").
+			Reply(200).
+			JSON(fakeContents("approvers:\n- carol\nreviewers:\n- \"@aliases-owner/reviewers\"\n"))
+		gock.New("https://api.github.com").
+			Get("/repos/aliases-owner/aliases-repo/contents/OWNERS_ALIASES").
+			Reply(200).
+			JSON(fakeContents("aliases:\n  sig-root:\n  - alice\n  - \"@aliases-owner/maintainers\"\n"))
+		gock.New("https://api.github.com").
+			Get("/orgs/aliases-owner/teams/maintainers/members").
+			Reply(200).
+			JSON([]map[string]string{{"login": "bob"}, {"login": "alice"}})
+		gock.New("https://api.github.com").
+			Get("/orgs/aliases-owner/teams/reviewers/members").
+			Reply(200).
+			JSON([]map[string]string{{"login": "dave"}})
+
+		client := pluginhelpers.OwnersClientFromGithub(
+			github.NewClient(nil), "OWNERS",
+			pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](),
+			pluginhelpers.NewConfigCache[plugins.OwnersAliases](),
+		)
+		cfg, err := client.GetOwners("aliases-owner", "aliases-repo", "pkg/foo.go")
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(cfg.Path).Should(Equal("pkg"))
+		Expect(cfg.Approvers).Should(Equal([]string{"carol", "alice", "bob"}))
+		Expect(cfg.Reviewers).Should(Equal([]string{"dave"}))
+	})
+
+	It("Should skip teams which cannot be listed", func() {
+		gock.New("https://api.github.com").
+			Get("/search/code").
+			Reply(200).
+			JSON(map[string]interface{}{"items": []map[string]string{{"path": "OWNERS"}}})
+		gock.New("https://api.github.com").
+			Get("/repos/teams-owner/teams-repo/contents/OWNERS").
+			Reply(200).
+			JSON(fakeContents("approvers:\n- alice\n- \"@teams-owner/deleted\"\n- \"@teams-owner/secret\"\n"))
+		gock.New("https://api.github.com").
+			Get("/repos/teams-owner/teams-repo/contents/OWNERS_ALIASES").
+			Reply(404).JSON(map[string]string{"message": "Not Found"})
+		gock.New("https://api.github.com").
+			Get("/orgs/teams-owner/teams/deleted/members").
+			Reply(404).JSON(map[string]string{"message": "Not Found"})
+		gock.New("https://api.github.com").
+			Get("/orgs/teams-owner/teams/secret/members").
+			Reply(403).JSON(map[string]string{"message": "Resource not accessible by integration"})
+
+		client := pluginhelpers.OwnersClientFromGithub(
+			github.NewClient(nil), "OWNERS",
+			pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration](),
+			pluginhelpers.NewConfigCache[plugins.OwnersAliases](),
+		)
+		cfg, err := client.GetOwners("teams-owner", "teams-repo", "main.go")
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(cfg.Approvers).Should(Equal([]string{"alice"}))
+	})
+})
@@ -0,0 +1,75 @@
+package pluginhelpers_test
+
+import (
+	"context"
+
+	"github.com/google/go-github/v48/github"
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("GitRepoClient", func() {
+	client := pluginhelpers.GitRepoClientFromGithub(github.NewClient(nil))
+	repo := plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}}
+
+	BeforeEach(func() {
+		gock.DisableNetworking()
+	})
+	AfterEach(func() {
+		gock.Off()
+	})
+
+	It("Should approve the waiting workflow runs of the head SHA", func() {
+		gock.New("https://api.github.com").Get("/repos/airconduct/kuilei/actions/runs").
+			MatchParam("status", "action_required").
+			Reply(200).JSON(map[string]interface{}{"total_count": 2, "workflow_runs": []map[string]interface{}{
+			{"id": 1, "head_sha": "head-sha"},
+			{"id": 2, "head_sha": "other-sha"},
+		}})
+		gock.New("https://api.github.com").Post("/repos/airconduct/kuilei/actions/runs/1/approve").Reply(201)
+
+		Expect(client.ApproveWorkflowRuns(context.TODO(), repo, "head-sha")).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+	})
+
+	It("Should list check runs of all pages", func() {
+		gock.New("https://api.github.com").Get("/repos/airconduct/kuilei/commits/head-sha/check-runs").
+			MatchParam("per_page", "100").
+			Reply(200).
+			SetHeader("Link", `<https://api.github.com/repos/airconduct/kuilei/commits/head-sha/check-runs?page=2&per_page=100>; rel="next"`).
+			JSON(map[string]interface{}{"total_count": 2, "check_runs": []map[string]interface{}{{"id": 1, "name": "build"}}})
+		gock.New("https://api.github.com").Get("/repos/airconduct/kuilei/commits/head-sha/check-runs").
+			MatchParam("page", "2").
+			Reply(200).
+			JSON(map[string]interface{}{"total_count": 2, "check_runs": []map[string]interface{}{{"id": 2, "name": "build (ubuntu-latest)"}}})
+
+		checks, err := client.ListChecks(context.TODO(), repo, "head-sha")
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(checks).Should(HaveLen(2))
+		Expect(checks[1].Name).Should(Equal("build (ubuntu-latest)"))
+	})
+})
+
+var _ = Describe("GitOrgClient", func() {
+	client := pluginhelpers.GitOrgClientFromGithub(github.NewClient(nil))
+
+	BeforeEach(func() {
+		gock.DisableNetworking()
+	})
+	AfterEach(func() {
+		gock.Off()
+	})
+
+	It("Should treat a missing team as having no members", func() {
+		gock.New("https://api.github.com").Get("/orgs/someone/teams/milestone-maintainers/members").
+			Reply(404).JSON(map[string]interface{}{"message": "Not Found"})
+
+		Expect(client.IsTeamMember(context.TODO(), "someone", "milestone-maintainers", "foo")).Should(BeFalse())
+		Expect(gock.IsDone()).Should(BeTrue())
+	})
+})
@@ -0,0 +1,51 @@
+package pluginhelpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v48/github"
+
+	"github.com/airconduct/go-probot"
+)
+
+// getContentsIfModified fetches the contents, the blob SHA and the ETag of a file in the default branch.
+// If etag is not empty, a conditional request is sent, and modified is false when the file
+// has not changed since the response of etag.
+func getContentsIfModified(
+	ctx context.Context, gh *probot.GitHubClient,
+	owner, repo, path, etag string,
+) (contents, sha, newETag string, modified bool, err error) {
+	escapedPath := (&url.URL{Path: strings.Trim(path, "/")}).String()
+	req, err := gh.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, escapedPath), nil)
+	if err != nil {
+		return "", "", "", false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	file := new(github.RepositoryContent)
+	resp, err := gh.Do(ctx, req, file)
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return "", "", etag, false, nil
+	}
+	if err != nil {
+		return "", "", "", false, err
+	}
+	contents, err = file.GetContent()
+	if err != nil {
+		return "", "", "", false, err
+	}
+	return contents, file.GetSHA(), resp.Header.Get("ETag"), true, nil
+}
+
+// isNotFound returns true if err is a 404 response from GitHub.
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
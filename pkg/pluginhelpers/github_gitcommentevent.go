@@ -12,20 +12,22 @@ func GitCommentEventFromGithubIssueCommentEvent(event *github.IssueCommentEvent)
 	}
 	return plugins.GitCommentEvent{
 		GitComment: plugins.GitComment{
-			ID:           int(event.Issue.GetID()),
-			NodeID:       event.Issue.GetNodeID(),
-			CommentID:    int(event.Comment.GetID()),
-			IsPR:         event.Issue.PullRequestLinks != nil,
-			Body:         event.Comment.GetBody(),
-			HTMLURL:      event.Comment.GetHTMLURL(),
-			Number:       event.Issue.GetNumber(),
-			User:         plugins.GitUser{Name: event.Comment.User.GetLogin()},
-			IssueAuthor:  plugins.GitUser{Name: event.Issue.User.GetLogin()},
-			Assignees:    assignees,
-			IssueState:   event.Issue.GetState(),
-			IssueTitle:   event.Issue.GetTitle(),
-			IssueBody:    event.Issue.GetBody(),
-			IssueHTMLURL: event.Issue.GetHTMLURL(),
+			ID:                int(event.Issue.GetID()),
+			NodeID:            event.Issue.GetNodeID(),
+			CommentID:         int(event.Comment.GetID()),
+			IsPR:              event.Issue.PullRequestLinks != nil,
+			Body:              event.Comment.GetBody(),
+			HTMLURL:           event.Comment.GetHTMLURL(),
+			Number:            event.Issue.GetNumber(),
+			User:              plugins.GitUser{Name: event.Comment.User.GetLogin()},
+			IssueAuthor:       plugins.GitUser{Name: event.Issue.User.GetLogin()},
+			Assignees:         assignees,
+			IssueState:        event.Issue.GetState(),
+			IssueTitle:        event.Issue.GetTitle(),
+			IssueBody:         event.Issue.GetBody(),
+			IssueHTMLURL:      event.Issue.GetHTMLURL(),
+			Labels:            GitLabelsFromGithub(event.Issue.Labels),
+			AuthorAssociation: event.Comment.GetAuthorAssociation(),
 		},
 		Action: plugins.GitCommentEventAction(event.GetAction()),
 		Repo: plugins.GitRepo{
@@ -42,18 +44,20 @@ func GitCommentEventFromGithubIssuesEvent(event *github.IssuesEvent) plugins.Git
 	}
 	return plugins.GitCommentEvent{
 		GitComment: plugins.GitComment{
-			ID:           int(event.Issue.GetID()),
-			NodeID:       event.Issue.GetNodeID(),
-			IsPR:         event.Issue.PullRequestLinks != nil,
-			Body:         event.Issue.GetBody(),
-			Number:       event.Issue.GetNumber(),
-			User:         plugins.GitUser{Name: event.Issue.User.GetLogin()},
-			IssueAuthor:  plugins.GitUser{Name: event.Issue.User.GetLogin()},
-			Assignees:    assignees,
-			IssueState:   event.Issue.GetState(),
-			IssueTitle:   event.Issue.GetTitle(),
-			IssueBody:    event.Issue.GetBody(),
-			IssueHTMLURL: event.Issue.GetHTMLURL(),
+			ID:                int(event.Issue.GetID()),
+			NodeID:            event.Issue.GetNodeID(),
+			IsPR:              event.Issue.PullRequestLinks != nil,
+			Body:              event.Issue.GetBody(),
+			Number:            event.Issue.GetNumber(),
+			User:              plugins.GitUser{Name: event.Issue.User.GetLogin()},
+			IssueAuthor:       plugins.GitUser{Name: event.Issue.User.GetLogin()},
+			Assignees:         assignees,
+			IssueState:        event.Issue.GetState(),
+			IssueTitle:        event.Issue.GetTitle(),
+			IssueBody:         event.Issue.GetBody(),
+			IssueHTMLURL:      event.Issue.GetHTMLURL(),
+			Labels:            GitLabelsFromGithub(event.Issue.Labels),
+			AuthorAssociation: event.Issue.GetAuthorAssociation(),
 		},
 		Action: plugins.GitCommentEventAction(event.GetAction()),
 		Repo: plugins.GitRepo{
@@ -70,18 +74,21 @@ func GitCommentEventFromGithubPullRequestEvent(event *github.PullRequestEvent) p
 	}
 	return plugins.GitCommentEvent{
 		GitComment: plugins.GitComment{
-			ID:           int(event.PullRequest.GetID()),
-			NodeID:       event.PullRequest.GetNodeID(),
-			IsPR:         true,
-			Body:         event.PullRequest.GetBody(),
-			Number:       event.PullRequest.GetNumber(),
-			User:         plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
-			IssueAuthor:  plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
-			Assignees:    assignees,
-			IssueState:   event.PullRequest.GetState(),
-			IssueTitle:   event.PullRequest.GetTitle(),
-			IssueBody:    event.PullRequest.GetBody(),
-			IssueHTMLURL: event.PullRequest.GetHTMLURL(),
+			ID:                int(event.PullRequest.GetID()),
+			NodeID:            event.PullRequest.GetNodeID(),
+			IsPR:              true,
+			Body:              event.PullRequest.GetBody(),
+			Number:            event.PullRequest.GetNumber(),
+			User:              plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
+			IssueAuthor:       plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
+			Assignees:         assignees,
+			IssueState:        event.PullRequest.GetState(),
+			IssueTitle:        event.PullRequest.GetTitle(),
+			IssueBody:         event.PullRequest.GetBody(),
+			IssueHTMLURL:      event.PullRequest.GetHTMLURL(),
+			Labels:            GitLabelsFromGithub(event.PullRequest.Labels),
+			BaseRef:           event.PullRequest.Base.GetRef(),
+			AuthorAssociation: event.PullRequest.GetAuthorAssociation(),
 		},
 		Action: plugins.GitCommentEventAction(event.GetAction()),
 		Repo: plugins.GitRepo{
@@ -98,19 +105,22 @@ func GitCommentEventFromGithubPullRequestReviewEvent(event *github.PullRequestRe
 	}
 	return plugins.GitCommentEvent{
 		GitComment: plugins.GitComment{
-			ID:           int(event.PullRequest.GetID()),
-			NodeID:       event.PullRequest.GetNodeID(),
-			CommentID:    int(event.Review.GetID()),
-			IsPR:         true,
-			Body:         event.Review.GetBody(),
-			Number:       event.PullRequest.GetNumber(),
-			User:         plugins.GitUser{Name: event.Review.User.GetLogin()},
-			IssueAuthor:  plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
-			Assignees:    assignees,
-			IssueState:   event.PullRequest.GetState(),
-			IssueTitle:   event.PullRequest.GetTitle(),
-			IssueBody:    event.PullRequest.GetBody(),
-			IssueHTMLURL: event.PullRequest.GetHTMLURL(),
+			ID:                int(event.PullRequest.GetID()),
+			NodeID:            event.PullRequest.GetNodeID(),
+			CommentID:         int(event.Review.GetID()),
+			IsPR:              true,
+			Body:              event.Review.GetBody(),
+			Number:            event.PullRequest.GetNumber(),
+			User:              plugins.GitUser{Name: event.Review.User.GetLogin()},
+			IssueAuthor:       plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
+			Assignees:         assignees,
+			IssueState:        event.PullRequest.GetState(),
+			IssueTitle:        event.PullRequest.GetTitle(),
+			IssueBody:         event.PullRequest.GetBody(),
+			IssueHTMLURL:      event.PullRequest.GetHTMLURL(),
+			Labels:            GitLabelsFromGithub(event.PullRequest.Labels),
+			BaseRef:           event.PullRequest.Base.GetRef(),
+			AuthorAssociation: event.Review.GetAuthorAssociation(),
 		},
 		Action: plugins.GitCommentEventAction(event.GetAction()),
 		Repo: plugins.GitRepo{
@@ -127,19 +137,22 @@ func GitCommentEventFromGithubPullRequestReviewCommentEvent(event *github.PullRe
 	}
 	return plugins.GitCommentEvent{
 		GitComment: plugins.GitComment{
-			ID:           int(event.PullRequest.GetID()),
-			NodeID:       event.PullRequest.GetNodeID(),
-			CommentID:    int(event.Comment.GetID()),
-			IsPR:         true,
-			Body:         event.Comment.GetBody(),
-			Number:       event.PullRequest.GetNumber(),
-			User:         plugins.GitUser{Name: event.Comment.User.GetLogin()},
-			IssueAuthor:  plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
-			Assignees:    assignees,
-			IssueState:   event.PullRequest.GetState(),
-			IssueTitle:   event.PullRequest.GetTitle(),
-			IssueBody:    event.PullRequest.GetBody(),
-			IssueHTMLURL: event.PullRequest.GetHTMLURL(),
+			ID:                int(event.PullRequest.GetID()),
+			NodeID:            event.PullRequest.GetNodeID(),
+			CommentID:         int(event.Comment.GetID()),
+			IsPR:              true,
+			Body:              event.Comment.GetBody(),
+			Number:            event.PullRequest.GetNumber(),
+			User:              plugins.GitUser{Name: event.Comment.User.GetLogin()},
+			IssueAuthor:       plugins.GitUser{Name: event.PullRequest.User.GetLogin()},
+			Assignees:         assignees,
+			IssueState:        event.PullRequest.GetState(),
+			IssueTitle:        event.PullRequest.GetTitle(),
+			IssueBody:         event.PullRequest.GetBody(),
+			IssueHTMLURL:      event.PullRequest.GetHTMLURL(),
+			Labels:            GitLabelsFromGithub(event.PullRequest.Labels),
+			BaseRef:           event.PullRequest.Base.GetRef(),
+			AuthorAssociation: event.Comment.GetAuthorAssociation(),
 		},
 		Action: plugins.GitCommentEventAction(event.GetAction()),
 		Repo: plugins.GitRepo{
@@ -0,0 +1,45 @@
+package pluginhelpers_test
+
+import (
+	"github.com/google/go-github/v48/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("GitCommentEvent converters", func() {
+	repo := &github.Repository{Name: github.String("repo"), Owner: &github.User{Login: github.String("owner")}}
+
+	It("Should convert reopened issues events", func() {
+		e := pluginhelpers.GitCommentEventFromGithubIssuesEvent(&github.IssuesEvent{
+			Action: github.String("reopened"),
+			Issue: &github.Issue{
+				Number: github.Int(1), State: github.String("open"),
+				User: &github.User{Login: github.String("author")},
+			},
+			Repo: repo,
+		})
+		Expect(e.Action).Should(Equal(plugins.GitCommentActionReopened))
+		Expect(e.IsPR).Should(BeFalse())
+		Expect(e.IssueState).Should(Equal("open"))
+		Expect(e.Repo).Should(Equal(plugins.GitRepo{Name: "repo", Owner: plugins.GitUser{Name: "owner"}}))
+	})
+	It("Should convert reopened pull request events", func() {
+		e := pluginhelpers.GitCommentEventFromGithubPullRequestEvent(&github.PullRequestEvent{
+			Action: github.String("reopened"),
+			PullRequest: &github.PullRequest{
+				Number: github.Int(2), State: github.String("open"),
+				User: &github.User{Login: github.String("author")},
+				Base: &github.PullRequestBranch{Ref: github.String("main")},
+			},
+			Repo: repo,
+		})
+		Expect(e.Action).Should(Equal(plugins.GitCommentActionReopened))
+		Expect(e.IsPR).Should(BeTrue())
+		Expect(e.Number).Should(Equal(2))
+		Expect(e.BaseRef).Should(Equal("main"))
+		Expect(e.IssueAuthor.Name).Should(Equal("author"))
+	})
+})
@@ -2,8 +2,12 @@ package pluginhelpers
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/shurcooL/githubv4"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/airconduct/go-probot"
 	"github.com/airconduct/kuilei/pkg/plugins"
@@ -208,3 +212,291 @@ type prSearchQuery struct {
 		} `graphql:"pullRequests(first:100,states:$states,orderBy:{field:CREATED_AT,direction:ASC})"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
 }
+
+// searchResultLimit is the max number of results GitHub search returns for a query.
+const searchResultLimit = 1000
+
+// searchEpoch is a time before any pull request on GitHub, the start of unbounded searches.
+var searchEpoch = time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (c *githubGraphqlClient) SearchMergedPR(
+	ctx context.Context, repo plugins.GitRepo, base string, since, until time.Time,
+) ([]plugins.GitPullRequest, error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	if since.Before(searchEpoch) {
+		since = searchEpoch
+	}
+	prs, err := c.searchMergedPR(ctx, repo, base, since.UTC().Truncate(time.Second), until.UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(prs, func(i, j int) bool { return prs[i].MergedAt.Before(prs[j].MergedAt) })
+	return prs, nil
+}
+
+// searchMergedPR searches pull requests merged in [since, until). GitHub search returns
+// searchResultLimit results at most, so that a window with more results is split in halves.
+func (c *githubGraphqlClient) searchMergedPR(
+	ctx context.Context, repo plugins.GitRepo, base string, since, until time.Time,
+) ([]plugins.GitPullRequest, error) {
+	if !since.Before(until) {
+		return nil, nil
+	}
+	// The range of merged qualifier is inclusive, so that the end is moved back by one second
+	query := fmt.Sprintf("repo:%s/%s is:pr is:merged merged:%s..%s",
+		repo.Owner.Name, repo.Name,
+		since.Format(time.RFC3339), until.Add(-time.Second).Format(time.RFC3339),
+	)
+	if base != "" {
+		query += " base:" + base
+	}
+	var prs []plugins.GitPullRequest
+	variables := map[string]interface{}{
+		"query":  githubv4.String(query),
+		"cursor": (*githubv4.String)(nil),
+	}
+	for {
+		q := &mergedPRSearchQuery{}
+		if err := c.Client.Query(ctx, q, variables); err != nil {
+			return nil, err
+		}
+		if q.Search.IssueCount > searchResultLimit {
+			mid := since.Add(until.Sub(since) / 2).Truncate(time.Second)
+			if !mid.After(since) {
+				return nil, fmt.Errorf("more than %d pull requests are merged at %s", searchResultLimit, since.Format(time.RFC3339))
+			}
+			first, err := c.searchMergedPR(ctx, repo, base, since, mid)
+			if err != nil {
+				return nil, err
+			}
+			second, err := c.searchMergedPR(ctx, repo, base, mid, until)
+			if err != nil {
+				return nil, err
+			}
+			return append(first, second...), nil
+		}
+		for _, node := range q.Search.Nodes {
+			prs = append(prs, node.PullRequest.toGitPullRequest())
+		}
+		if !q.Search.PageInfo.HasNextPage {
+			return prs, nil
+		}
+		variables["cursor"] = githubv4.NewString(q.Search.PageInfo.EndCursor)
+	}
+}
+
+func (c *githubGraphqlClient) ListMergedPRBetween(
+	ctx context.Context, repo plugins.GitRepo, from, to string,
+) ([]plugins.GitPullRequest, error) {
+	if to == "" {
+		q := &defaultBranchQuery{}
+		if err := c.Client.Query(ctx, q, map[string]interface{}{
+			"owner": githubv4.String(repo.Owner.Name),
+			"repo":  githubv4.String(repo.Name),
+		}); err != nil {
+			return nil, err
+		}
+		to = q.Repository.DefaultBranchRef.Name
+	}
+	var (
+		candidates []mergedPullRequestNode
+		commits    = sets.NewString()
+	)
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(repo.Owner.Name),
+		"repo":   githubv4.String(repo.Name),
+		"from":   githubv4.String(from),
+		"to":     githubv4.String(to),
+		"cursor": (*githubv4.String)(nil),
+	}
+	for {
+		q := &compareQuery{}
+		if err := c.Client.Query(ctx, q, variables); err != nil {
+			return nil, err
+		}
+		ref := q.Repository.Ref
+		if ref.Name == "" {
+			return nil, fmt.Errorf("ref %s not found", from)
+		}
+		if ref.Compare.Status == "" {
+			return nil, fmt.Errorf("ref %s not found", to)
+		}
+		for _, commit := range ref.Compare.Commits.Nodes {
+			commits.Insert(commit.Oid)
+			candidates = append(candidates, commit.AssociatedPullRequests.Nodes...)
+		}
+		if !ref.Compare.Commits.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(ref.Compare.Commits.PageInfo.EndCursor)
+	}
+
+	// A commit is also associated with the pull requests merged into other branches, only
+	// those merged by a commit in the range are selected.
+	var prs []plugins.GitPullRequest
+	seen := sets.NewInt()
+	for _, pr := range candidates {
+		if !pr.Merged || !commits.Has(pr.MergeCommit.Oid) || seen.Has(pr.Number) {
+			continue
+		}
+		seen.Insert(pr.Number)
+		prs = append(prs, pr.toGitPullRequest())
+	}
+	return prs, nil
+}
+
+// mergedPullRequestNode contains the fields of a merged pull request.
+type mergedPullRequestNode struct {
+	Number      int
+	Title       string
+	Body        string
+	URL         string `graphql:"url"`
+	Merged      bool
+	MergedAt    githubv4.DateTime
+	BaseRefName string
+	MergeCommit struct {
+		Oid string
+	}
+	Author struct {
+		Login string
+	}
+	Labels struct {
+		Nodes []struct {
+			Name  string
+			Color string
+		}
+	} `graphql:"labels(first:100)"`
+}
+
+func (pr mergedPullRequestNode) toGitPullRequest() plugins.GitPullRequest {
+	labels := []plugins.Label{}
+	for _, l := range pr.Labels.Nodes {
+		labels = append(labels, plugins.Label{Name: l.Name, Color: l.Color})
+	}
+	return plugins.GitPullRequest{
+		Number:   pr.Number,
+		State:    plugins.PullRequestStateMerged,
+		Title:    pr.Title,
+		Body:     pr.Body,
+		URL:      pr.URL,
+		Merged:   true,
+		MergedAt: pr.MergedAt.Time,
+		Base:     plugins.GitBranch{Ref: pr.BaseRefName},
+		Labels:   labels,
+		User:     plugins.GitUser{Name: pr.Author.Login},
+	}
+}
+
+type mergedPRSearchQuery struct {
+	Search struct {
+		IssueCount int
+		PageInfo   struct {
+			HasNextPage bool
+			EndCursor   githubv4.String
+		}
+		Nodes []struct {
+			PullRequest mergedPullRequestNode `graphql:"... on PullRequest"`
+		}
+	} `graphql:"search(query: $query, type: ISSUE, first: 100, after: $cursor)"`
+}
+
+type defaultBranchQuery struct {
+	Repository struct {
+		DefaultBranchRef struct {
+			Name string
+		}
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type compareQuery struct {
+	Repository struct {
+		Ref struct {
+			Name    string
+			Compare struct {
+				Status  string
+				Commits struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+					Nodes []struct {
+						Oid                    string
+						AssociatedPullRequests struct {
+							Nodes []mergedPullRequestNode
+						} `graphql:"associatedPullRequests(first: 10)"`
+					}
+				} `graphql:"commits(first: 100, after: $cursor)"`
+			} `graphql:"compare(headRef: $to)"`
+		} `graphql:"ref(qualifiedName: $from)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+func (c *githubGraphqlClient) SearchIssues(ctx context.Context, repo plugins.GitRepo, query string) ([]plugins.GitIssue, error) {
+	var issues []plugins.GitIssue
+	variables := map[string]interface{}{
+		"query":  githubv4.String(fmt.Sprintf("repo:%s/%s %s", repo.Owner.Name, repo.Name, query)),
+		"cursor": (*githubv4.String)(nil),
+	}
+	for {
+		q := &issueSearchQuery{}
+		if err := c.Client.Query(ctx, q, variables); err != nil {
+			return nil, err
+		}
+		for _, node := range q.Search.Nodes {
+			issue, isPR := node.Issue, node.Typename == "PullRequest"
+			if isPR {
+				issue = node.PullRequest
+			}
+			labels := []plugins.Label{}
+			for _, l := range issue.Labels.Nodes {
+				labels = append(labels, plugins.Label{Name: l.Name, Color: l.Color})
+			}
+			issues = append(issues, plugins.GitIssue{
+				Number:    issue.Number,
+				State:     issue.State,
+				Title:     issue.Title,
+				Labels:    labels,
+				User:      plugins.GitUser{Name: issue.Author.Login},
+				IsPR:      isPR,
+				UpdatedAt: issue.UpdatedAt.Time,
+			})
+		}
+		if !q.Search.PageInfo.HasNextPage {
+			return issues, nil
+		}
+		variables["cursor"] = githubv4.NewString(q.Search.PageInfo.EndCursor)
+	}
+}
+
+// issueSearchNode contains the fields shared by issues and pull requests in search results.
+type issueSearchNode struct {
+	Number    int
+	State     string
+	Title     string
+	UpdatedAt githubv4.DateTime
+	Author    struct {
+		Login string
+	}
+	Labels struct {
+		Nodes []struct {
+			Name  string
+			Color string
+		}
+	} `graphql:"labels(first:100)"`
+}
+
+type issueSearchQuery struct {
+	Search struct {
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   githubv4.String
+		}
+		Nodes []struct {
+			Typename    string          `graphql:"__typename"`
+			Issue       issueSearchNode `graphql:"... on Issue"`
+			PullRequest issueSearchNode `graphql:"... on PullRequest"`
+		}
+	} `graphql:"search(query: $query, type: ISSUE, first: 100, after: $cursor)"`
+}
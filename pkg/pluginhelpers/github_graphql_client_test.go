@@ -0,0 +1,164 @@
+package pluginhelpers_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/shurcooL/githubv4"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("GitSearchClient", func() {
+	client := pluginhelpers.GitSearchClientFromGithub(githubv4.NewClient(&http.Client{}))
+	repo := plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}}
+	prNode := func(number int, mergedAt string) map[string]interface{} {
+		return map[string]interface{}{
+			"number": number, "title": "foo", "body": "bar", "url": "https://github.com/airconduct/kuilei/pull/1",
+			"mergedAt": mergedAt, "baseRefName": "main", "author": map[string]string{"login": "alice"},
+			"labels": map[string]interface{}{"nodes": []map[string]string{{"name": "kind/bug", "color": "red"}}},
+		}
+	}
+
+	BeforeEach(func() {
+		gock.DisableNetworking()
+	})
+	AfterEach(func() {
+		gock.Off()
+	})
+
+	It("Should search merged pull requests of all pages", func() {
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`merged:2023-01-01T00:00:00Z..2023-01-31T23:59:59Z base:main`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"search": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "c1"},
+			"nodes":    []interface{}{prNode(2, "2023-01-03T00:00:00Z")},
+		}}})
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`"cursor":"c1"`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"search": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": false},
+			"nodes":    []interface{}{prNode(1, "2023-01-02T00:00:00Z")},
+		}}})
+
+		prs, err := client.SearchMergedPR(context.TODO(), repo, "main",
+			time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC))
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(prs).Should(HaveLen(2))
+		Expect(prs[0].Number).Should(Equal(1))
+		Expect(prs[1].Number).Should(Equal(2))
+		Expect(prs[0].Merged).Should(BeTrue())
+		Expect(prs[0].URL).Should(Equal("https://github.com/airconduct/kuilei/pull/1"))
+		Expect(prs[0].User.Name).Should(Equal("alice"))
+		Expect(prs[0].Labels).Should(Equal([]plugins.Label{{Name: "kind/bug", Color: "red"}}))
+	})
+
+	It("Should split windows with more results than the search limit", func() {
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`merged:2023-01-01T00:00:00Z..2023-01-02T23:59:59Z"`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"search": map[string]interface{}{
+			"issueCount": 1500,
+			"pageInfo":   map[string]interface{}{"hasNextPage": true, "endCursor": "c1"},
+			"nodes":      []interface{}{prNode(1, "2023-01-01T01:00:00Z")},
+		}}})
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`merged:2023-01-01T00:00:00Z..2023-01-01T23:59:59Z"`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"search": map[string]interface{}{
+			"issueCount": 1,
+			"pageInfo":   map[string]interface{}{"hasNextPage": false},
+			"nodes":      []interface{}{prNode(1, "2023-01-01T01:00:00Z")},
+		}}})
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`merged:2023-01-02T00:00:00Z..2023-01-02T23:59:59Z"`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"search": map[string]interface{}{
+			"issueCount": 1,
+			"pageInfo":   map[string]interface{}{"hasNextPage": false},
+			"nodes":      []interface{}{prNode(2, "2023-01-02T01:00:00Z")},
+		}}})
+
+		prs, err := client.SearchMergedPR(context.TODO(), repo, "",
+			time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC))
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(prs).Should(HaveLen(2))
+		Expect(prs[0].Number).Should(Equal(1))
+		Expect(prs[1].Number).Should(Equal(2))
+	})
+
+	It("Should list pull requests merged by the commits between refs", func() {
+		associated := func(number int, merged bool, mergeCommit string) map[string]interface{} {
+			node := prNode(number, "2023-01-02T00:00:00Z")
+			node["merged"], node["mergeCommit"] = merged, map[string]string{"oid": mergeCommit}
+			return node
+		}
+		commit := func(oid string, prs ...interface{}) map[string]interface{} {
+			return map[string]interface{}{"oid": oid, "associatedPullRequests": map[string]interface{}{"nodes": prs}}
+		}
+		compare := func(hasNextPage bool, commits ...interface{}) map[string]interface{} {
+			return map[string]interface{}{"data": map[string]interface{}{"repository": map[string]interface{}{
+				"ref": map[string]interface{}{"name": "v0.1.0", "compare": map[string]interface{}{
+					"status": "AHEAD",
+					"commits": map[string]interface{}{
+						"pageInfo": map[string]interface{}{"hasNextPage": hasNextPage, "endCursor": "c1"},
+						"nodes":    commits,
+					},
+				}},
+			}}}
+		}
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`"from":"v0.1.0".*"to":"v0.2.0"`).
+			Reply(200).JSON(compare(true,
+			commit("a1", associated(1, true, "a2"), associated(2, true, "b1"), associated(3, false, "")),
+			commit("a2", associated(1, true, "a2")),
+		))
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`"cursor":"c1"`).
+			Reply(200).JSON(compare(false,
+			commit("a3", associated(4, true, "a3")),
+		))
+
+		prs, err := client.ListMergedPRBetween(context.TODO(), repo, "v0.1.0", "v0.2.0")
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(prs).Should(HaveLen(2))
+		Expect(prs[0].Number).Should(Equal(1))
+		Expect(prs[1].Number).Should(Equal(4))
+		Expect(prs[0].Merged).Should(BeTrue())
+		Expect(prs[0].Base.Ref).Should(Equal("main"))
+	})
+
+	It("Should compare with the default branch", func() {
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`defaultBranchRef`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"repository": map[string]interface{}{
+			"defaultBranchRef": map[string]interface{}{"name": "main"},
+		}}})
+		gock.New("https://api.github.com").Post("/graphql").
+			BodyString(`"to":"main"`).
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"repository": map[string]interface{}{
+			"ref": map[string]interface{}{"name": "v0.1.0", "compare": map[string]interface{}{
+				"status": "IDENTICAL", "commits": map[string]interface{}{"nodes": []interface{}{}},
+			}},
+		}}})
+
+		prs, err := client.ListMergedPRBetween(context.TODO(), repo, "v0.1.0", "")
+		Expect(err).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(prs).Should(BeEmpty())
+	})
+
+	It("Should fail if the ref is not found", func() {
+		gock.New("https://api.github.com").Post("/graphql").
+			Reply(200).JSON(map[string]interface{}{"data": map[string]interface{}{"repository": map[string]interface{}{
+			"ref": nil,
+		}}})
+		_, err := client.ListMergedPRBetween(context.TODO(), repo, "v0.0.0", "main")
+		Expect(err).Should(MatchError("ref v0.0.0 not found"))
+	})
+})
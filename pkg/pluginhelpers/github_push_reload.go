@@ -0,0 +1,81 @@
+package pluginhelpers
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v48/github"
+
+	"github.com/airconduct/go-probot"
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/syncer"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// ReloadConfigFromGithubPush refetches the plugin config, OWNERS and OWNERS_ALIASES files which are
+// touched by a push to the default branch. Repos whose files have never been loaded are
+// skipped, they will be fetched on demand.
+func ReloadConfigFromGithubPush(
+	ctx context.Context, gh *probot.GitHubClient, event *github.PushEvent,
+	configPath, ownersFileName string,
+	pluginConfigCache ConfigCache[plugins.Configuration],
+	ownersConfigCache NearestConfigCache[plugins.OwnersConfiguration],
+	ownersAliasesCache ConfigCache[plugins.OwnersAliases],
+) error {
+	if event.GetRef() != "refs/heads/"+event.Repo.GetDefaultBranch() {
+		return nil
+	}
+	owner, repo := event.Repo.Owner.GetLogin(), event.Repo.GetName()
+	if owner == "" {
+		owner = event.Repo.Owner.GetName()
+	}
+
+	configClient := &githubPluginConfigClient{ghClient: gh, configPath: configPath, configCache: pluginConfigCache}
+	ownersClient := &githubOwnersClient{
+		ghClient: gh, ownersFileName: ownersFileName,
+		configCache: ownersConfigCache, aliasesCache: ownersAliasesCache,
+	}
+	for _, file := range touchedFiles(event.Commits) {
+		switch {
+		case file == strings.Trim(configPath, "/"):
+			if !syncer.CacheSyncer.Has(syncer.CacheSyncFuncKey{Owner: owner, Repo: repo, Kind: PluginConfigSyncKind}) {
+				continue
+			}
+			if err := configClient.syncConfigFromRemote(owner, repo); err != nil && !isNotFound(err) {
+				return err
+			}
+		case path.Base(file) == ownersFileName:
+			if !syncer.CacheSyncer.Has(syncer.CacheSyncFuncKey{Owner: owner, Repo: repo, Kind: OwnersSyncKind}) {
+				continue
+			}
+			if err := ownersClient.syncOwnersFileFromRemote(ctx, owner, repo, file); err != nil {
+				return err
+			}
+		case file == OwnersAliasesFileName:
+			if !syncer.CacheSyncer.Has(syncer.CacheSyncFuncKey{Owner: owner, Repo: repo, Kind: OwnersSyncKind}) {
+				continue
+			}
+			if err := ownersClient.syncOwnersAliasesFromRemote(ctx, owner, repo); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// touchedFiles returns all files added, modified or removed by the commits.
+func touchedFiles(commits []*github.HeadCommit) []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, commit := range commits {
+		for _, list := range [][]string{commit.Added, commit.Modified, commit.Removed} {
+			for _, f := range list {
+				if !seen[f] {
+					seen[f] = true
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,116 @@
+package pluginhelpers_test
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/google/go-github/v48/github"
+	"github.com/h2non/gock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+func fakeContents(contents string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "file",
+		"encoding": "base64",
+		"content":  base64.StdEncoding.EncodeToString([]byte(contents)),
+	}
+}
+
+var _ = Describe("ReloadConfigFromGithubPush", func() {
+	gh := github.NewClient(nil)
+	var (
+		configCache  pluginhelpers.ConfigCache[plugins.Configuration]
+		ownersCache  pluginhelpers.NearestConfigCache[plugins.OwnersConfiguration]
+		aliasesCache pluginhelpers.ConfigCache[plugins.OwnersAliases]
+	)
+	pushEvent := func(ref string, commits ...*github.HeadCommit) *github.PushEvent {
+		return &github.PushEvent{
+			Ref: github.String(ref),
+			Repo: &github.PushEventRepository{
+				Name:          github.String("reload-repo"),
+				DefaultBranch: github.String("main"),
+				Owner:         &github.User{Login: github.String("reload-owner")},
+			},
+			Commits: commits,
+		}
+	}
+
+	BeforeEach(func() {
+		gock.DisableNetworking()
+		configCache = pluginhelpers.NewConfigCache[plugins.Configuration]()
+		ownersCache = pluginhelpers.NewConfigNearestCache[plugins.OwnersConfiguration]()
+		aliasesCache = pluginhelpers.NewConfigCache[plugins.OwnersAliases]()
+
+		// Each spec starts with the config loaded on demand at ETag "v1"
+		gock.New("https://api.github.com").
+			Get("/repos/reload-owner/reload-repo/contents/.github/kuilei.yml").
+			Reply(200).SetHeader("ETag", `"v1"`).
+			JSON(fakeContents("plugins:\n- name: lgtm\n"))
+		cfg, err := pluginhelpers.PluginConfigClientFromGithub(gh, ".github/kuilei.yml", configCache).
+			GetConfig("reload-owner", "reload-repo")
+		Expect(err).Should(Succeed())
+		Expect(cfg.Plugins).Should(Equal([]plugins.PluginConfiguration{{Name: "lgtm"}}))
+		Expect(gock.IsDone()).Should(BeTrue())
+	})
+	AfterEach(func() {
+		gock.Off()
+	})
+
+	It("Should ignore pushes to other branches and unrelated files", func() {
+		Expect(pluginhelpers.ReloadConfigFromGithubPush(
+			context.TODO(), gh, pushEvent("refs/heads/dev", &github.HeadCommit{Modified: []string{".github/kuilei.yml"}}),
+			".github/kuilei.yml", "OWNERS", configCache, ownersCache, aliasesCache,
+		)).Should(Succeed())
+		Expect(pluginhelpers.ReloadConfigFromGithubPush(
+			context.TODO(), gh, pushEvent("refs/heads/main", &github.HeadCommit{Modified: []string{"main.go"}}),
+			".github/kuilei.yml", "OWNERS", configCache, ownersCache, aliasesCache,
+		)).Should(Succeed())
+		Expect(configCache.Get("reload-owner", "reload-repo", ".github/kuilei.yml").Plugins).
+			Should(Equal([]plugins.PluginConfiguration{{Name: "lgtm"}}))
+	})
+
+	It("Should not replace config when it is not modified", func() {
+		gock.New("https://api.github.com").
+			Get("/repos/reload-owner/reload-repo/contents/.github/kuilei.yml").
+			MatchHeader("If-None-Match", `"v1"`).
+			Reply(304)
+		Expect(pluginhelpers.ReloadConfigFromGithubPush(
+			context.TODO(), gh, pushEvent("refs/heads/main", &github.HeadCommit{Modified: []string{".github/kuilei.yml"}}),
+			".github/kuilei.yml", "OWNERS", configCache, ownersCache, aliasesCache,
+		)).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(configCache.Get("reload-owner", "reload-repo", ".github/kuilei.yml").Plugins).
+			Should(Equal([]plugins.PluginConfiguration{{Name: "lgtm"}}))
+	})
+
+	It("Should reload config when it is modified", func() {
+		gock.New("https://api.github.com").
+			Get("/repos/reload-owner/reload-repo/contents/.github/kuilei.yml").
+			MatchHeader("If-None-Match", `"v1"`).
+			Reply(200).SetHeader("ETag", `"v2"`).
+			JSON(fakeContents("plugins:\n- name: approve\n"))
+		Expect(pluginhelpers.ReloadConfigFromGithubPush(
+			context.TODO(), gh, pushEvent("refs/heads/main", &github.HeadCommit{Modified: []string{".github/kuilei.yml"}}),
+			".github/kuilei.yml", "OWNERS", configCache, ownersCache, aliasesCache,
+		)).Should(Succeed())
+		Expect(gock.IsDone()).Should(BeTrue())
+		Expect(configCache.Get("reload-owner", "reload-repo", ".github/kuilei.yml").Plugins).
+			Should(Equal([]plugins.PluginConfiguration{{Name: "approve"}}))
+	})
+
+	It("Should drop config when it is removed", func() {
+		gock.New("https://api.github.com").
+			Get("/repos/reload-owner/reload-repo/contents/.github/kuilei.yml").
+			Reply(404).JSON(map[string]string{"message": "Not Found"})
+		Expect(pluginhelpers.ReloadConfigFromGithubPush(
+			context.TODO(), gh, pushEvent("refs/heads/main", &github.HeadCommit{Removed: []string{".github/kuilei.yml"}}),
+			".github/kuilei.yml", "OWNERS", configCache, ownersCache, aliasesCache,
+		)).Should(Succeed())
+		Expect(configCache.Get("reload-owner", "reload-repo", ".github/kuilei.yml")).Should(BeNil())
+	})
+})
@@ -0,0 +1,79 @@
+package pluginhelpers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v48/github"
+
+	"github.com/airconduct/go-probot"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// teamMembersCacheTTL is how long the members of a GitHub team are cached.
+const teamMembersCacheTTL = 10 * time.Minute
+
+// teamMembers caches the logins of team members, keyed by org/team-slug.
+var teamMembers = newLRUCache[[]string](1000, teamMembersCacheTTL)
+
+// listTeamMembers returns the logins of all members of a team, including the members of child teams.
+// A team which does not exist, e.g. a deleted team or one in a user account, has no members.
+func listTeamMembers(ctx context.Context, gh *probot.GitHubClient, org, slug string) ([]string, error) {
+	key := fmt.Sprintf("%s/%s", org, slug)
+	if members, ok := teamMembers.Get(key); ok {
+		return members, nil
+	}
+	var members []string
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		users, resp, err := gh.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+		if isNotFound(err) {
+			teamMembers.Add(key, nil)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			members = append(members, u.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	teamMembers.Add(key, members)
+	return members, nil
+}
+
+// expandTeams replaces the "@org/team-slug" entries of logins with the members of the teams.
+// Teams which cannot be listed are skipped, so that the other owners still apply.
+func expandTeams(ctx context.Context, gh *probot.GitHubClient, logins []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	add := func(logins ...string) {
+		for _, login := range logins {
+			if key := strings.ToLower(login); !seen[key] {
+				seen[key] = true
+				out = append(out, login)
+			}
+		}
+	}
+	for _, login := range logins {
+		org, slug, ok := plugins.ParseTeam(login)
+		if !ok {
+			add(login)
+			continue
+		}
+		members, err := listTeamMembers(ctx, gh, org, slug)
+		if err != nil {
+			log.Printf("Failed to list members of team %s, skip it, error: %v", login, err)
+			continue
+		}
+		add(members...)
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package pluginhelpers
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether name matches the shell pattern.
+// Besides the syntax supported by path.Match, a "**" path segment matches
+// zero or more directories, e.g. "api/**" matches "api/v1/types.go".
+func MatchGlob(pattern, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	name = strings.Trim(name, "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// MatchAnyGlob reports whether name matches at least one of the patterns.
+func MatchAnyGlob(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// Collapse consecutive "**"
+			for len(patterns) > 0 && patterns[0] == "**" {
+				patterns = patterns[1:]
+			}
+			if len(patterns) == 0 {
+				return true
+			}
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(patterns, names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(patterns[0], names[0]); err != nil || !ok {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
@@ -0,0 +1,149 @@
+package pluginhelpers
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is a LRU cache whose entries also expire after ttl.
+// A non-positive size or ttl disables the corresponding bound.
+type lruCache[V any] struct {
+	mutex sync.Mutex
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+
+	ll    *list.List
+	items map[string]*list.Element
+
+	// onEvict is called with the values evicted by the size or the ttl bound, out of the lock.
+	onEvict func(key string, value V)
+}
+
+type lruItem[V any] struct {
+	key     string
+	value   V
+	savedAt time.Time
+}
+
+func newLRUCache[V any](size int, ttl time.Duration) *lruCache[V] {
+	return &lruCache[V]{
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value of key and marks it as recently used. Expired values are removed.
+func (c *lruCache[V]) Get(key string) (V, bool) {
+	c.mutex.Lock()
+
+	var zero V
+	elem, ok := c.items[key]
+	if !ok {
+		c.mutex.Unlock()
+		return zero, false
+	}
+	item := elem.Value.(*lruItem[V])
+	if c.ttl > 0 && c.now().Sub(item.savedAt) > c.ttl {
+		c.removeElement(elem)
+		c.mutex.Unlock()
+		c.evicted(item)
+		return zero, false
+	}
+	c.ll.MoveToFront(elem)
+	c.mutex.Unlock()
+	return item.value, true
+}
+
+// Add saves the value of key, the least recently used value is evicted if the cache is full.
+func (c *lruCache[V]) Add(key string, value V) {
+	c.mutex.Lock()
+
+	if elem, ok := c.items[key]; ok {
+		item := elem.Value.(*lruItem[V])
+		item.value, item.savedAt = value, c.now()
+		c.ll.MoveToFront(elem)
+		c.mutex.Unlock()
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruItem[V]{key: key, value: value, savedAt: c.now()})
+	var evicted *lruItem[V]
+	if c.size > 0 && c.ll.Len() > c.size {
+		evicted = c.ll.Back().Value.(*lruItem[V])
+		c.removeElement(c.ll.Back())
+	}
+	c.mutex.Unlock()
+	if evicted != nil {
+		c.evicted(evicted)
+	}
+}
+
+// Touch renews the age of the value of key and marks it as recently used, e.g. when the value
+// is confirmed to be up to date. It returns false if key is not cached.
+func (c *lruCache[V]) Touch(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	elem.Value.(*lruItem[V]).savedAt = c.now()
+	c.ll.MoveToFront(elem)
+	return true
+}
+
+// Remove removes the value of key.
+func (c *lruCache[V]) Remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// RemoveFunc removes all values whose key matches fn.
+func (c *lruCache[V]) RemoveFunc(fn func(key string) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, elem := range c.items {
+		if fn(key) {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// Range calls fn for each unexpired value, from the most to the least recently used.
+func (c *lruCache[V]) Range(fn func(key string, value V, savedAt time.Time)) {
+	c.mutex.Lock()
+	var items []lruItem[V]
+	for elem := c.ll.Front(); elem != nil; elem = elem.Next() {
+		item := elem.Value.(*lruItem[V])
+		if c.ttl > 0 && c.now().Sub(item.savedAt) > c.ttl {
+			continue
+		}
+		items = append(items, *item)
+	}
+	c.mutex.Unlock()
+
+	for _, item := range items {
+		fn(item.key, item.value, item.savedAt)
+	}
+}
+
+func (c *lruCache[V]) evicted(item *lruItem[V]) {
+	if c.onEvict != nil {
+		c.onEvict(item.key, item.value)
+	}
+}
+
+func (c *lruCache[V]) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*lruItem[V]).key)
+}
@@ -0,0 +1,106 @@
+package pluginhelpers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// NewPluginConditionMatcher returns a matcher to check plugin conditions against one event.
+// The base branch and changed files of the pull request are fetched lazily and only once,
+// so the same matcher should be shared by all plugins executed for the event.
+func NewPluginConditionMatcher(prClient plugins.GitPRClient, eventType string, e plugins.GitCommentEvent) *PluginConditionMatcher {
+	return &PluginConditionMatcher{
+		prClient:  prClient,
+		eventType: eventType,
+		event:     e,
+		baseRef:   e.BaseRef,
+	}
+}
+
+type PluginConditionMatcher struct {
+	prClient  plugins.GitPRClient
+	eventType string
+	event     plugins.GitCommentEvent
+
+	baseRef string
+	files   []plugins.GitCommitFile
+	loaded  bool
+}
+
+// Match returns true if the event matches all conditions.
+func (m *PluginConditionMatcher) Match(ctx context.Context, cond plugins.PluginConditions) (bool, error) {
+	if cond.IsEmpty() {
+		return true, nil
+	}
+	if len(cond.Events) > 0 && !ContainsFold(cond.Events, m.eventType) {
+		return false, nil
+	}
+	if len(cond.AuthorAssociations) > 0 && !ContainsFold(cond.AuthorAssociations, m.event.AuthorAssociation) {
+		return false, nil
+	}
+	if len(cond.Branches) == 0 && len(cond.Paths) == 0 {
+		return true, nil
+	}
+	// Branches and paths only make sense for pull requests
+	if !m.event.IsPR {
+		return false, nil
+	}
+	if len(cond.Branches) > 0 {
+		base, err := m.getBaseRef(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !MatchAnyGlob(cond.Branches, base) {
+			return false, nil
+		}
+	}
+	if len(cond.Paths) > 0 {
+		files, err := m.getFiles(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, f := range files {
+			if MatchAnyGlob(cond.Paths, f.Path) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *PluginConditionMatcher) getBaseRef(ctx context.Context) (string, error) {
+	if m.baseRef != "" {
+		return m.baseRef, nil
+	}
+	pr, err := m.prClient.GetPR(ctx, m.event.Repo, m.event.Number)
+	if err != nil {
+		return "", err
+	}
+	m.baseRef = pr.Base.Ref
+	return m.baseRef, nil
+}
+
+func (m *PluginConditionMatcher) getFiles(ctx context.Context) ([]plugins.GitCommitFile, error) {
+	if m.loaded {
+		return m.files, nil
+	}
+	files, err := m.prClient.ListFiles(ctx, m.event.Repo, plugins.GitPullRequest{Number: m.event.Number})
+	if err != nil {
+		return nil, err
+	}
+	m.files, m.loaded = files, true
+	return m.files, nil
+}
+
+// ContainsFold reports whether values contains v, ignoring case.
+func ContainsFold(values []string, v string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package pluginhelpers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Glob", func() {
+	It("Should match globs", func() {
+		Expect(pluginhelpers.MatchGlob("api/**", "api/v1/types.go")).Should(BeTrue())
+		Expect(pluginhelpers.MatchGlob("api/**", "api")).Should(BeTrue())
+		Expect(pluginhelpers.MatchGlob("api/**", "pkg/api/types.go")).Should(BeFalse())
+		Expect(pluginhelpers.MatchGlob("**/*.md", "README.md")).Should(BeTrue())
+		Expect(pluginhelpers.MatchGlob("**/*.md", "docs/a/b.md")).Should(BeTrue())
+		Expect(pluginhelpers.MatchGlob("docs/*.md", "docs/a/b.md")).Should(BeFalse())
+		Expect(pluginhelpers.MatchGlob("release-*", "release-1.0")).Should(BeTrue())
+		Expect(pluginhelpers.MatchGlob("release-*", "main")).Should(BeFalse())
+	})
+})
+
+var _ = Describe("PluginConditionMatcher", func() {
+	listFilesCalled := 0
+	prClient := mock.FakeGitPRClient(
+		func(ctx context.Context, gr plugins.GitRepo, gpr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+			listFilesCalled++
+			return []plugins.GitCommitFile{{Path: "api/v1/types.go"}, {Path: "README.md"}}, nil
+		},
+		func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+			return plugins.GitPullRequest{Number: number, Base: plugins.GitBranch{Ref: "release-1.0"}}, nil
+		},
+		nil,
+	)
+	prEvent := plugins.GitCommentEvent{
+		GitComment: plugins.GitComment{IsPR: true, Number: 1, AuthorAssociation: "MEMBER"},
+	}
+
+	It("Should match empty conditions", func() {
+		matcher := pluginhelpers.NewPluginConditionMatcher(prClient, "issues", plugins.GitCommentEvent{})
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{})).Should(BeTrue())
+	})
+	It("Should match events and author associations", func() {
+		matcher := pluginhelpers.NewPluginConditionMatcher(prClient, "issue_comment", prEvent)
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Events: []string{"issue_comment"}})).Should(BeTrue())
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Events: []string{"pull_request"}})).Should(BeFalse())
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{AuthorAssociations: []string{"member"}})).Should(BeTrue())
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{AuthorAssociations: []string{"OWNER"}})).Should(BeFalse())
+	})
+	It("Should match branches and paths", func() {
+		listFilesCalled = 0
+		matcher := pluginhelpers.NewPluginConditionMatcher(prClient, "issue_comment", prEvent)
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Branches: []string{"main", "release-*"}})).Should(BeTrue())
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Branches: []string{"main"}})).Should(BeFalse())
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Paths: []string{"api/**"}})).Should(BeTrue())
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Paths: []string{"pkg/**"}})).Should(BeFalse())
+		Expect(listFilesCalled).Should(Equal(1))
+	})
+	It("Should not match branches of issues", func() {
+		matcher := pluginhelpers.NewPluginConditionMatcher(prClient, "issues", plugins.GitCommentEvent{})
+		Expect(matcher.Match(context.TODO(), plugins.PluginConditions{Branches: []string{"*"}})).Should(BeFalse())
+	})
+})
@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the next activation time after the given time.
+type Schedule interface {
+	// Next returns the first activation time after t, or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// MinEvery is the shortest interval of "@every <duration>" schedules, shorter ones are
+// rejected so that a config cannot make a plugin scan a repo over and over.
+const MinEvery = time.Minute
+
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a cron expression with five fields (minute, hour, day of month, month
+// and day of week), a descriptor like "@hourly", or "@every <duration>".
+// Cron expressions are evaluated in UTC, and "@every" intervals must be at least MinEvery.
+func ParseSchedule(spec string) (Schedule, error) {
+	return parseSchedule(spec, MinEvery)
+}
+
+func parseSchedule(spec string, minEvery time.Duration) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q, %w", spec, err)
+		}
+		if d < minEvery {
+			return nil, fmt.Errorf("invalid schedule %q, interval must be at least %s", spec, minEvery)
+		}
+		return everySchedule(d), nil
+	}
+	if expr, ok := scheduleDescriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q, expected 5 fields, got %d", spec, len(fields))
+	}
+	s := &cronSchedule{
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+	}
+	var err error
+	for i, f := range []struct {
+		bits     *uint64
+		min, max int
+		names    []string
+	}{
+		{&s.minute, 0, 59, nil},
+		{&s.hour, 0, 23, nil},
+		{&s.dom, 1, 31, nil},
+		{&s.month, 1, 12, monthNames},
+		{&s.dow, 0, 7, dowNames},
+	} {
+		if *f.bits, err = parseCronField(fields[i], f.min, f.max, f.names); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q, %w", spec, err)
+		}
+	}
+	// Both 0 and 7 are Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+var (
+	monthNames = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dowNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// parseCronField parses a comma separated list of "*", "a", "a-b", each with an optional "/step",
+// into a bit set of the values.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			var err error
+			rng = item[:idx]
+			if step, err = strconv.Atoi(item[idx+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", item)
+			}
+		}
+		lo, hi := min, max
+		if rng != "*" && rng != "?" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], min, max, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = parseCronValue(bounds[1], min, max, names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "a/step" means from a to the max
+				hi = max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", item)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, min, max)
+	}
+	return v, nil
+}
+
+// everySchedule activates at a fixed interval after the given time.
+type everySchedule time.Duration
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// cronSchedule holds the values of each cron field as a bit set.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// A day matches if either the day of month or the day of week matches,
+	// unless one of them is "*".
+	domStar, dowStar bool
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// A valid expression fires at least once in a few years, e.g. on Feb 29
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,57 @@
+package scheduler_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/scheduler"
+)
+
+var _ = Describe("Schedule", func() {
+	// Monday
+	now := time.Date(2023, 1, 2, 10, 17, 30, 0, time.UTC)
+
+	DescribeTable("Should return the next activation time",
+		func(spec string, expected time.Time) {
+			s, err := scheduler.ParseSchedule(spec)
+			Expect(err).Should(Succeed())
+			Expect(s.Next(now)).Should(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2023, 1, 2, 10, 18, 0, 0, time.UTC)),
+		Entry("hourly", "@hourly", time.Date(2023, 1, 2, 11, 0, 0, 0, time.UTC)),
+		Entry("daily", "@daily", time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)),
+		Entry("weekly", "@weekly", time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC)),
+		Entry("monthly", "@monthly", time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("step", "*/15 * * * *", time.Date(2023, 1, 2, 10, 30, 0, 0, time.UTC)),
+		Entry("start with step", "5/20 9-11 * * *", time.Date(2023, 1, 2, 10, 25, 0, 0, time.UTC)),
+		Entry("list and range", "30 2,8 * * 3-5", time.Date(2023, 1, 4, 2, 30, 0, 0, time.UTC)),
+		Entry("names", "0 0 * feb sun", time.Date(2023, 2, 5, 0, 0, 0, 0, time.UTC)),
+		Entry("sunday as 7", "0 12 * * 7", time.Date(2023, 1, 8, 12, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 15 * 2", time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("every", "@every 90s", now.Add(90*time.Second)),
+	)
+
+	It("Should never fire on a day which does not exist", func() {
+		s, err := scheduler.ParseSchedule("0 0 30 2 *")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(now).IsZero()).Should(BeTrue())
+	})
+
+	DescribeTable("Should reject invalid schedules",
+		func(spec string) {
+			_, err := scheduler.ParseSchedule(spec)
+			Expect(err).Should(HaveOccurred())
+		},
+		Entry("too few fields", "* * * *"),
+		Entry("out of range", "60 * * * *"),
+		Entry("reversed range", "* 5-2 * * *"),
+		Entry("zero step", "*/0 * * * *"),
+		Entry("bad name", "* * * foo *"),
+		Entry("bad duration", "@every soon"),
+		Entry("negative duration", "@every -1m"),
+		Entry("too short duration", "@every 30s"),
+	)
+})
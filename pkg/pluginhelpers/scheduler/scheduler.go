@@ -0,0 +1,270 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// DefaultSchedule is the schedule of periodic plugins without one in config.
+const DefaultSchedule = "@hourly"
+
+type Options struct {
+	// Stagger is the max delay added to the scheduled times of a job. Each job gets a fixed
+	// delay derived from its repo and plugin, so that jobs of the same schedule are spread over time.
+	Stagger time.Duration
+	// Timeout is the max duration of each run, 0 means no timeout.
+	Timeout time.Duration
+	// Tick is the interval to check for due jobs, defaults to 1s.
+	Tick time.Duration
+	// MinEvery is the shortest interval of "@every" schedules, defaults to MinEvery.
+	MinEvery time.Duration
+}
+
+// JobKey identifies the job of a periodic plugin in a repo.
+type JobKey struct {
+	Owner  string
+	Repo   string
+	Plugin string
+}
+
+func (k JobKey) String() string {
+	return fmt.Sprintf("%s/%s:%s", k.Owner, k.Repo, k.Plugin)
+}
+
+// JobStatus is the schedule and the outcome of the last run of a job.
+type JobStatus struct {
+	Owner    string     `json:"owner"`
+	Repo     string     `json:"repo"`
+	Plugin   string     `json:"plugin"`
+	Schedule string     `json:"schedule"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	LastRun  *RunResult `json:"last_run,omitempty"`
+	Runs     int        `json:"runs"`
+	Failures int        `json:"failures"`
+	// Error is set if the schedule is invalid, the job never runs then.
+	Error string `json:"error,omitempty"`
+}
+
+// RunResult is the outcome of a run.
+type RunResult struct {
+	Start    time.Time `json:"start"`
+	Duration string    `json:"duration"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type job struct {
+	key        JobKey
+	status     JobStatus
+	schedule   Schedule
+	offset     time.Duration
+	args       []string
+	clientSets plugins.ClientSets
+}
+
+// next returns the first staggered activation time after now.
+func (j *job) next(now time.Time) *time.Time {
+	if j.schedule == nil {
+		return nil
+	}
+	t := j.schedule.Next(now.Add(-j.offset))
+	if t.IsZero() {
+		return nil
+	}
+	t = t.Add(j.offset)
+	return &t
+}
+
+// Scheduler runs the periodic plugins of repos on the schedules in their configs.
+// Jobs are run one by one, so that runs due at the same time do not burst the API.
+type Scheduler struct {
+	opts Options
+
+	mutex sync.Mutex
+	jobs  map[JobKey]*job
+}
+
+func New(opts Options) *Scheduler {
+	if opts.Tick <= 0 {
+		opts.Tick = time.Second
+	}
+	if opts.MinEvery <= 0 {
+		opts.MinEvery = MinEvery
+	}
+	return &Scheduler{opts: opts, jobs: make(map[JobKey]*job)}
+}
+
+// Register updates the jobs of repo by the periodic plugins in its config. The runs use the
+// clientSets, which should be scoped to the installation of the repo. The status of a job is
+// kept unless its schedule changes, jobs of plugins removed from the config are dropped.
+func (s *Scheduler) Register(repo plugins.GitRepo, cfg plugins.Configuration, clientSets plugins.ClientSets) {
+	now := time.Now()
+	keep := map[JobKey]bool{}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, p := range cfg.Plugins {
+		if !plugins.IsPeriodicPlugin(p.Name) {
+			continue
+		}
+		key := JobKey{Owner: repo.Owner.Name, Repo: repo.Name, Plugin: p.Name}
+		keep[key] = true
+		spec := p.Schedule
+		if spec == "" {
+			spec = DefaultSchedule
+		}
+		if j, ok := s.jobs[key]; ok && j.status.Schedule == spec {
+			j.args, j.clientSets = p.Args, clientSets
+			continue
+		}
+		j := &job{
+			key:        key,
+			status:     JobStatus{Owner: key.Owner, Repo: key.Repo, Plugin: key.Plugin, Schedule: spec},
+			offset:     s.offsetOf(key),
+			args:       p.Args,
+			clientSets: clientSets,
+		}
+		schedule, err := parseSchedule(spec, s.opts.MinEvery)
+		if err != nil {
+			j.status.Error = err.Error()
+		} else {
+			j.schedule = schedule
+			if j.status.NextRun = j.next(now); j.status.NextRun == nil {
+				j.status.Error = fmt.Sprintf("schedule %q never fires", spec)
+			}
+		}
+		if j.status.Error != "" {
+			loggerOf(clientSets).Error(nil, "Invalid schedule of periodic plugin", "job", key, "error", j.status.Error)
+		}
+		s.jobs[key] = j
+	}
+	for key := range s.jobs {
+		if key.Owner == repo.Owner.Name && key.Repo == repo.Name && !keep[key] {
+			delete(s.jobs, key)
+		}
+	}
+}
+
+// Forget drops all jobs of a repo.
+func (s *Scheduler) Forget(owner, repo string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key := range s.jobs {
+		if key.Owner == owner && key.Repo == repo {
+			delete(s.jobs, key)
+		}
+	}
+}
+
+// Jobs returns the status of all jobs, sorted by repo and plugin.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mutex.Lock()
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, j.status)
+	}
+	s.mutex.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Owner != out[j].Owner {
+			return out[i].Owner < out[j].Owner
+		}
+		if out[i].Repo != out[j].Repo {
+			return out[i].Repo < out[j].Repo
+		}
+		return out[i].Plugin < out[j].Plugin
+	})
+	return out
+}
+
+// Start runs the due jobs in background until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	go wait.UntilWithContext(ctx, s.runDue, s.opts.Tick)
+}
+
+func (s *Scheduler) runDue(ctx context.Context) {
+	now := time.Now()
+	// Copy the due jobs, so that the lock is not held during the runs
+	s.mutex.Lock()
+	var due []job
+	for _, j := range s.jobs {
+		if j.status.NextRun != nil && !j.status.NextRun.After(now) {
+			due = append(due, *j)
+		}
+	}
+	s.mutex.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].status.NextRun.Before(*due[j].status.NextRun) })
+
+	for _, j := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		result := s.run(ctx, j)
+		s.mutex.Lock()
+		// The job may have been dropped or rescheduled during the run
+		if current, ok := s.jobs[j.key]; ok {
+			current.status.LastRun = &result
+			current.status.Runs++
+			if result.Error != "" {
+				current.status.Failures++
+			}
+			current.status.NextRun = current.next(time.Now())
+		}
+		s.mutex.Unlock()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j job) (result RunResult) {
+	log := loggerOf(j.clientSets).WithName("scheduler").WithValues("job", j.key)
+	if s.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.Timeout)
+		defer cancel()
+	}
+	result.Start = time.Now()
+	defer func() {
+		if e := recover(); e != nil {
+			result.Error = fmt.Sprintf("panic: %v", e)
+		}
+		result.Duration = time.Since(result.Start).String()
+		if result.Error != "" {
+			log.Error(nil, "Periodic plugin failed", "duration", result.Duration, "error", result.Error)
+			return
+		}
+		log.Info("Periodic plugin succeeded", "duration", result.Duration)
+	}()
+
+	plugin := plugins.GetPeriodicPlugin(j.key.Plugin, j.clientSets, j.args...)
+	if plugin == nil {
+		result.Error = "plugin not found"
+		return
+	}
+	if err := plugin.Run(ctx, plugins.GitRepo{Owner: plugins.GitUser{Name: j.key.Owner}, Name: j.key.Repo}); err != nil {
+		result.Error = err.Error()
+	}
+	return
+}
+
+// offsetOf returns the fixed delay of a job in [0, Stagger).
+func (s *Scheduler) offsetOf(key JobKey) time.Duration {
+	if s.opts.Stagger <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key.String()))
+	return time.Duration(h.Sum64() % uint64(s.opts.Stagger))
+}
+
+func loggerOf(clientSets plugins.ClientSets) logr.Logger {
+	if clientSets.LoggerClient == nil {
+		return logr.Discard()
+	}
+	return clientSets.LoggerClient.GetLogger()
+}
@@ -0,0 +1,143 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers/scheduler"
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Scheduler", func() {
+	lock := sync.Mutex{}
+	runs := map[string][]string{}
+	plugins.RegisterPeriodicPlugin("fake-periodic", func(cs plugins.ClientSets) plugins.PeriodicPlugin {
+		return &fakePeriodicPlugin{run: func(ctx context.Context, repo plugins.GitRepo, fail bool) error {
+			lock.Lock()
+			defer lock.Unlock()
+			runs[repo.Owner.Name+"/"+repo.Name] = append(runs[repo.Owner.Name+"/"+repo.Name], "run")
+			if fail {
+				return errors.New("boom")
+			}
+			return nil
+		}}
+	})
+	runsOf := func(repo string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(runs[repo])
+	}
+	repo := func(owner, name string) plugins.GitRepo {
+		return plugins.GitRepo{Owner: plugins.GitUser{Name: owner}, Name: name}
+	}
+	periodicConfig := func(schedule string, args ...string) plugins.Configuration {
+		return plugins.Configuration{Plugins: []plugins.PluginConfiguration{
+			{Name: "label"},
+			{Name: "fake-periodic", Schedule: schedule, Args: args},
+		}}
+	}
+	clientSets := plugins.ClientSets{LoggerClient: mock.FakeLoggerClient()}
+
+	var (
+		s      *scheduler.Scheduler
+		cancel context.CancelFunc
+	)
+	BeforeEach(func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		s = scheduler.New(scheduler.Options{Tick: 10 * time.Millisecond, Timeout: time.Second, MinEvery: 50 * time.Millisecond})
+		s.Start(ctx)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("Should run periodic plugins on their schedules and report the outcomes", func() {
+		s.Register(repo("foo", "ok"), periodicConfig("@every 50ms"), clientSets)
+		s.Register(repo("foo", "fail"), periodicConfig("@every 50ms", "--fail"), clientSets)
+		Eventually(func() int { return runsOf("foo/ok") }, 2*time.Second, 10*time.Millisecond).Should(BeNumerically(">=", 2))
+		Eventually(func() int { return runsOf("foo/fail") }, 2*time.Second, 10*time.Millisecond).Should(BeNumerically(">=", 2))
+
+		jobs := s.Jobs()
+		Expect(jobs).Should(HaveLen(2))
+		Expect(jobs[0].Repo).Should(Equal("fail"))
+		Expect(jobs[0].Plugin).Should(Equal("fake-periodic"))
+		Expect(jobs[0].Schedule).Should(Equal("@every 50ms"))
+		Expect(jobs[0].Failures).Should(Equal(jobs[0].Runs))
+		Expect(jobs[0].LastRun.Error).Should(Equal("boom"))
+		Expect(jobs[1].Repo).Should(Equal("ok"))
+		Expect(jobs[1].Runs).Should(BeNumerically(">=", 1))
+		Expect(jobs[1].Failures).Should(BeZero())
+		Expect(jobs[1].LastRun.Error).Should(BeEmpty())
+		Expect(jobs[1].NextRun).ShouldNot(BeNil())
+	})
+
+	It("Should default to hourly and report invalid schedules", func() {
+		s.Register(repo("bar", "default"), periodicConfig(""), clientSets)
+		s.Register(repo("bar", "invalid"), periodicConfig("every day"), clientSets)
+		s.Register(repo("bar", "short"), periodicConfig("@every 10ms"), clientSets)
+		jobs := s.Jobs()
+		Expect(jobs).Should(HaveLen(3))
+		Expect(jobs[0].Schedule).Should(Equal(scheduler.DefaultSchedule))
+		Expect(jobs[0].NextRun.Sub(time.Now())).Should(BeNumerically("<=", time.Hour))
+		Expect(jobs[0].Error).Should(BeEmpty())
+		Expect(jobs[1].NextRun).Should(BeNil())
+		Expect(jobs[1].Error).Should(ContainSubstring("invalid schedule"))
+		Expect(jobs[2].NextRun).Should(BeNil())
+		Expect(jobs[2].Error).Should(ContainSubstring("interval must be at least 50ms"))
+	})
+
+	It("Should stagger jobs of the same schedule", func() {
+		staggered := scheduler.New(scheduler.Options{Stagger: time.Hour})
+		for _, name := range []string{"a", "b", "c", "d"} {
+			staggered.Register(repo("baz", name), periodicConfig("@hourly"), clientSets)
+		}
+		nextRuns := map[time.Time]bool{}
+		for _, job := range staggered.Jobs() {
+			nextRuns[*job.NextRun] = true
+		}
+		Expect(nextRuns).Should(HaveLen(4))
+	})
+
+	It("Should drop jobs removed from config or of forgotten repos", func() {
+		s.Register(repo("qux", "a"), periodicConfig("@every 50ms"), clientSets)
+		s.Register(repo("qux", "b"), periodicConfig("@every 50ms"), clientSets)
+		Expect(s.Jobs()).Should(HaveLen(2))
+		s.Register(repo("qux", "a"), plugins.Configuration{}, clientSets)
+		Expect(s.Jobs()).Should(HaveLen(1))
+		s.Forget("qux", "b")
+		Expect(s.Jobs()).Should(BeEmpty())
+	})
+})
+
+type fakePeriodicPlugin struct {
+	fail bool
+	run  func(ctx context.Context, repo plugins.GitRepo, fail bool) error
+}
+
+func (p *fakePeriodicPlugin) Name() string {
+	return "fake-periodic"
+}
+
+func (p *fakePeriodicPlugin) Description() string {
+	return "just a fake periodic plugin"
+}
+
+func (p *fakePeriodicPlugin) Usage() string {
+	return ""
+}
+
+func (p *fakePeriodicPlugin) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&p.fail, "fail", false, "")
+}
+
+func (p *fakePeriodicPlugin) Run(ctx context.Context, repo plugins.GitRepo) error {
+	return p.run(ctx, repo, p.fail)
+}
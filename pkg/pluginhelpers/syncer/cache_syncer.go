@@ -9,6 +9,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// ResyncPeriod is the base period of the background resync. Caches are kept up to date
+// by push events, the resync is only a safety net for missed events.
+var ResyncPeriod = time.Hour
+
+// ResyncJitterFactor spreads the resync of multiple replicas over time.
+const ResyncJitterFactor = 0.5
+
 var CacheSyncer = &cacheSyncer{
 	syncFuncs: make(map[CacheSyncFuncKey]func(owner string, repo string) error),
 }
@@ -40,15 +47,54 @@ func (c *cacheSyncer) EnsureSync(key CacheSyncFuncKey, fn func(owner, repo strin
 	c.startOnce.Do(c.startToSync)
 }
 
+// Has returns true if the cache of key has been loaded and is being synced.
+func (c *cacheSyncer) Has(key CacheSyncFuncKey) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	_, ok := c.syncFuncs[key]
+	return ok
+}
+
+// Remove stops the background resync of key.
+func (c *cacheSyncer) Remove(key CacheSyncFuncKey) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.syncFuncs, key)
+}
+
+// Forget stops the background resync of all kinds of caches of a repo.
+func (c *cacheSyncer) Forget(owner, repo string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.syncFuncs {
+		if key.Owner == owner && key.Repo == repo {
+			delete(c.syncFuncs, key)
+		}
+	}
+}
+
 func (c *cacheSyncer) startToSync() {
-	go wait.Forever(func() {
-		c.mutex.RLock()
-		defer c.mutex.RUnlock()
-
-		for key, fn := range c.syncFuncs {
-			if err := fn(key.Owner, key.Repo); err != nil {
-				log.Printf("Failed to sync function for key %v, error: %v", key, err)
-			}
+	go func() {
+		for {
+			// Caches are loaded on demand, so the first resync is also delayed
+			time.Sleep(wait.Jitter(ResyncPeriod, ResyncJitterFactor))
+			c.syncAll()
 		}
-	}, 5*time.Minute)
+	}()
+}
+
+func (c *cacheSyncer) syncAll() {
+	// Copy the functions, so that the lock is not held during the network calls
+	c.mutex.RLock()
+	syncFuncs := make(map[CacheSyncFuncKey]func(owner, repo string) error, len(c.syncFuncs))
+	for key, fn := range c.syncFuncs {
+		syncFuncs[key] = fn
+	}
+	c.mutex.RUnlock()
+
+	for key, fn := range syncFuncs {
+		if err := fn(key.Owner, key.Repo); err != nil {
+			log.Printf("Failed to sync function for key %v, error: %v", key, err)
+		}
+	}
 }
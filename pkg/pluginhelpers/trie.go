@@ -35,6 +35,23 @@ func (t *NearestTrie) Insert(path string) {
 	node.Leaf = true
 }
 
+func (t *NearestTrie) Remove(path string) {
+	t.Lock()
+	defer t.Unlock()
+
+	node := t.Root
+
+	path = filepath.Clean(strings.TrimLeft(path, "/"))
+	pathlist := strings.Split(path, string([]byte{filepath.Separator}))
+	for i := 0; i < len(pathlist); i++ {
+		node = node.Children[pathlist[i]]
+		if node == nil {
+			return
+		}
+	}
+	node.Leaf = false
+}
+
 func (t *NearestTrie) Search(path string) string {
 	t.RLock()
 	defer t.RUnlock()
@@ -59,3 +76,32 @@ func (t *NearestTrie) Search(path string) string {
 	}
 	return nearest
 }
+
+// SearchAll returns all inserted paths which are path itself or its parents, nearest first.
+func (t *NearestTrie) SearchAll(path string) []string {
+	t.RLock()
+	defer t.RUnlock()
+
+	node := t.Root
+
+	path = filepath.Clean(strings.TrimLeft(path, "/"))
+	pathlist := strings.Split(path, string([]byte{filepath.Separator}))
+
+	var parents []string
+	current := ""
+	for i := 0; i < len(pathlist); i++ {
+		key := pathlist[i]
+		if node.Leaf {
+			parents = append([]string{current}, parents...)
+		}
+		if node.Children[key] == nil {
+			return parents
+		}
+		current = filepath.Join(current, key)
+		node = node.Children[key]
+	}
+	if node.Leaf {
+		parents = append([]string{current}, parents...)
+	}
+	return parents
+}
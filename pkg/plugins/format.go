@@ -44,3 +44,24 @@ func FormatResponseRaw(body, bodyURL, login, reply string) string {
 func CleanMarkdownComments(body string) string {
 	return commentRegex.ReplaceAllString(body, "")
 }
+
+var releaseNoteRegex = regexp.MustCompile("(?s)```release-note[ \t]*\r?\n(.*?)```")
+
+// ExtractReleaseNote returns the content of the first ```release-note``` block in body,
+// ok is false if there is no such block. HTML comments in body are ignored.
+func ExtractReleaseNote(body string) (note string, ok bool) {
+	match := releaseNoteRegex.FindStringSubmatch(CleanMarkdownComments(body))
+	if match == nil {
+		return "", false
+	}
+	return strings.TrimSpace(match[1]), true
+}
+
+// IsReleaseNoteNone returns true if note is empty or states there is no release note, e.g. NONE.
+func IsReleaseNoteNone(note string) bool {
+	switch strings.ToLower(strings.TrimSpace(note)) {
+	case "", "none", "n/a", "na":
+		return true
+	}
+	return false
+}
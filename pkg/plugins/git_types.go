@@ -1,5 +1,7 @@
 package plugins
 
+import "time"
+
 type GitRepo struct {
 	Name  string
 	Owner GitUser
@@ -20,6 +22,8 @@ const (
 	GitCommentActionEdited GitCommentEventAction = "edited"
 	// GitCommentActionDeleted means something was deleted/dismissed.
 	GitCommentActionDeleted GitCommentEventAction = "deleted" // "dismissed"
+	// GitCommentActionReopened means an issue or pull request was reopened.
+	GitCommentActionReopened GitCommentEventAction = "reopened"
 )
 
 // GitCommentEvent is a fake event type that is instantiated for any git event that contains
@@ -28,8 +32,8 @@ const (
 // - issue_comment events
 // - pull_request_review events
 // - pull_request_review_comment events
-// - pull_request events with action in ["opened", "edited"]
-// - issue events with action in ["opened", "edited"]
+// - pull_request events with action in ["opened", "edited", "reopened"]
+// - issue events with action in ["opened", "edited", "reopened"]
 //
 // Issue and PR "closed" events are not coerced to the "deleted" Action and do not trigger
 // a GenericCommentEvent because these events don't actually remove the comment content from GH.
@@ -56,6 +60,12 @@ type GitComment struct {
 	IssueTitle   string
 	IssueBody    string
 	IssueHTMLURL string
+	// BaseRef is the base branch of the pull request, it is empty if the event payload does not contain it.
+	BaseRef string
+	// AuthorAssociation is the association of User with the repo, e.g. OWNER, MEMBER, CONTRIBUTOR.
+	AuthorAssociation string
+	// Labels are the labels of the issue or pull request when the event is sent.
+	Labels []Label
 }
 
 type GitIssueCommentEvent struct {
@@ -72,6 +82,8 @@ type GitIssueComment struct {
 	Body string
 	User GitUser
 	URL  string
+	// AuthorAssociation is the association of User with the repo, e.g. OWNER, MEMBER, CONTRIBUTOR.
+	AuthorAssociation string
 }
 
 type GitIssue struct {
@@ -84,8 +96,24 @@ type GitIssue struct {
 	Labels    []Label
 	Assignees []GitUser
 	User      GitUser
+	IsPR      bool
+	UpdatedAt time.Time
+}
+
+type GitMilestone struct {
+	Number int
+	Title  string
+	State  string
 }
 
+// GitIssueCloseReason is the reason an issue is closed for.
+type GitIssueCloseReason = string
+
+const (
+	GitIssueCloseReasonCompleted  GitIssueCloseReason = "completed"
+	GitIssueCloseReasonNotPlanned GitIssueCloseReason = "not_planned"
+)
+
 // GitPREventAction coerces multiple actions into its generic equivalent.
 type GitPREventAction string
 
@@ -127,6 +155,7 @@ type GitPullRequest struct {
 	Number    int
 	State     GitPullRequestState
 	Head      GitBranch
+	Base      GitBranch
 	Locked    bool
 	Title     string
 	Body      string
@@ -134,6 +163,15 @@ type GitPullRequest struct {
 	Labels    []Label
 	Assignees []GitUser
 	User      GitUser
+	// RequestedReviewers are the users whose review is requested and not submitted yet.
+	RequestedReviewers []GitUser
+	Merged             bool
+	Draft              bool
+	// URL is the HTML URL of the pull request.
+	URL      string
+	MergedAt time.Time
+	// MergeSHA is the commit a merged pull request is merged as, it is set by GetPR.
+	MergeSHA string
 }
 
 type GitPullRequestSearchResult struct {
@@ -150,12 +188,50 @@ type GitCommit struct {
 	Sha      string
 	Statuses []GitCommitStatus
 	Checks   []GitCommitCheck
+	// Message and Author are set by ListCommits.
+	Message string
+	Author  GitCommitAuthor
+}
+
+// GitCommitAuthor is the git author of a commit, who may have no account.
+type GitCommitAuthor struct {
+	Name  string
+	Email string
+}
+
+type GitCherryPickOptions struct {
+	// Onto is the branch to apply the commits onto.
+	Onto string
+	// Refs are fetched besides Onto, so that MergeSHA and HeadSHA are available.
+	Refs []string
+	// MergeSHA is the commit the pull request is merged as, and HeadSHA is the head of the pull
+	// request. A merge commit is applied as a whole, so that the commits merged from the base
+	// branch are left out, while a squashed commit or the rebased commits are applied as they are.
+	MergeSHA string
+	HeadSHA  string
+	// Branch is the branch to push the result to.
+	Branch string
 }
 
 type GitCommitFile struct {
 	Path string
+	// Status is one of the GitCommitFileStatus values.
+	Status GitCommitFileStatus
+	// Changes is the number of changed lines.
+	Changes   int
+	Additions int
+	Deletions int
 }
 
+type GitCommitFileStatus = string
+
+const (
+	GitCommitFileStatusAdded    GitCommitFileStatus = "added"
+	GitCommitFileStatusRemoved  GitCommitFileStatus = "removed"
+	GitCommitFileStatusModified GitCommitFileStatus = "modified"
+	GitCommitFileStatusRenamed  GitCommitFileStatus = "renamed"
+)
+
 type Label struct {
 	ID    int64
 	Name  string
@@ -203,6 +279,10 @@ const (
 )
 
 type GitCommitCheck struct {
+	// ID is the ID of the check run, it is not set by search results.
+	ID int64
+	// SuiteID is the ID of the check suite of the check run, it is not set by search results.
+	SuiteID    int64
 	Name       string
 	Status     GitCheckStatus
 	Conclusion GitCheckConclusion
@@ -16,21 +16,28 @@ var (
 	approveCancelRegex = regexp.MustCompile(`(?m)^/approve\s*(cancel)\s*$`)
 )
 
+// approveNotifyActions are the pull request actions which may change the files to approve.
+var approveNotifyActions = sets.NewString("opened", string(plugins.GitCommentActionReopened), "synchronize")
+
 func init() {
 	plugins.RegisterGitCommentPlugin("approve", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
 		plugin := &approvePlugin{
-			issueClient: cs.GitIssueClient,
-			prClient:    cs.GitPRClient,
-			ownerClient: cs.OwnersClient,
+			issueClient:  cs.GitIssueClient,
+			prClient:     cs.GitPRClient,
+			ownerClient:  cs.OwnersClient,
+			searchClient: cs.GitSearchClient,
+			botClient:    cs.BotClient,
 		}
 		return plugin
 	})
 }
 
 type approvePlugin struct {
-	issueClient plugins.GitIssueClient
-	prClient    plugins.GitPRClient
-	ownerClient plugins.OwnersClient
+	issueClient  plugins.GitIssueClient
+	prClient     plugins.GitPRClient
+	ownerClient  plugins.OwnersClient
+	searchClient plugins.GitSearchClient
+	botClient    plugins.BotClient
 
 	allowAuthor bool
 }
@@ -52,7 +59,21 @@ func (lp *approvePlugin) BindFlags(flags *pflag.FlagSet) {
 }
 
 func (lp *approvePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
-	if !e.IsPR || e.Action != plugins.GitCommentActionCreated {
+	if !e.IsPR {
+		return nil
+	}
+	if approveNotifyActions.Has(string(e.Action)) {
+		pr, err := lp.prClient.GetPR(ctx, e.Repo, e.Number)
+		if err != nil {
+			return err
+		}
+		state, err := lp.approvalState(ctx, e, pr)
+		if err != nil {
+			return err
+		}
+		return lp.update(ctx, e, pr, state)
+	}
+	if e.Action != plugins.GitCommentActionCreated {
 		return nil
 	}
 	// Check body
@@ -63,46 +84,136 @@ func (lp *approvePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) erro
 		return nil
 	}
 
+	pr, err := lp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
 	// Check author
-	if !lp.allowAuthor {
-		pr, err := lp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if !lp.allowAuthor && strings.EqualFold(pr.User.Name, e.User.Name) {
+		resp := "you cannot APPROVE your own PR."
+		return lp.issueClient.CreateIssueComment(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+
+	// Check owners config, only approvers can approve
+	state, err := lp.approvalState(ctx, e, pr)
+	if err != nil {
+		return err
+	}
+	user := strings.ToLower(e.User.Name)
+	if !state.allApprovers().Has(user) {
+		resp := "adding APPROVE is restricted to approvers in OWNERS files."
+		return lp.issueClient.CreateIssueComment(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+	// The event may not be listed in the comment history yet
+	if approveCancelMatch {
+		state.approvedBy.Delete(user)
+	} else {
+		state.approvedBy.Insert(user)
+	}
+	return lp.update(ctx, e, pr, state)
+}
+
+// update syncs the 'approved' label and the notifier comment with the approval state.
+func (lp *approvePlugin) update(ctx context.Context, e plugins.GitCommentEvent, pr plugins.GitPullRequest, state *approvalState) error {
+	issue := plugins.GitIssue{Number: e.Number}
+	approved := len(state.unapprovedFiles()) == 0
+	switch {
+	case approved && !hasLabel(pr.Labels, "approved"):
+		if err := lp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: "approved"}}); err != nil {
+			return err
+		}
+	case !approved && hasLabel(pr.Labels, "approved"):
+		if err := lp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: "approved"}); err != nil {
+			return err
+		}
+	}
+
+	var suggested []string
+	if !approved {
+		load, err := lp.reviewLoad(ctx, e.Repo)
 		if err != nil {
 			return err
 		}
-		if pr.User.Name == e.User.Name {
-			resp := "you cannot APPROVE your own PR."
-			return lp.issueClient.CreateIssueComment(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.GitIssueComment{
-				Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
-			})
+		suggested = state.suggestApprovers(load)
+	}
+	body := state.notification(suggested)
+	if state.notifier != nil {
+		if state.notifier.Body == body {
+			return nil
 		}
+		return lp.issueClient.EditIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{ID: state.notifier.ID, Body: body})
 	}
+	return lp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{Body: body})
+}
 
-	// Check owners config
-	reviewers := sets.NewString()
+// approvalState recomputes the approvals of the pull request from its files and comment history.
+func (lp *approvePlugin) approvalState(ctx context.Context, e plugins.GitCommentEvent, pr plugins.GitPullRequest) (*approvalState, error) {
 	files, err := lp.prClient.ListFiles(ctx, e.Repo, plugins.GitPullRequest{Number: e.Number})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	state := &approvalState{
+		author:     strings.ToLower(pr.User.Name),
+		owners:     make(map[string]string, len(files)),
+		units:      make(map[string]*approvalUnit),
+		approvedBy: sets.NewString(),
+		active:     sets.NewString(),
 	}
 	for _, file := range files {
-		owner, err := lp.ownerClient.GetOwners(e.Repo.Owner.Name, e.Repo.Name, file.Path)
+		owners, err := lp.ownerClient.GetOwners(e.Repo.Owner.Name, e.Repo.Name, file.Path)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		for _, name := range owner.Reviewers {
-			reviewers.Insert(strings.ToLower(name))
+		state.addFile(file.Path, owners)
+	}
+
+	comments, err := lp.issueClient.ListIssueComments(ctx, e.Repo, plugins.GitIssue{Number: e.Number})
+	if err != nil {
+		return nil, err
+	}
+	bot, err := lp.botClient.BotUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, comment := range comments {
+		body := plugins.CleanMarkdownComments(comment.Body)
+		if isBotComment(comment, bot, approveNotifierMarker) {
+			state.notifier = &comments[i]
+			continue
 		}
-		for _, name := range owner.Approvers {
-			reviewers.Insert(strings.ToLower(name))
+		user := strings.ToLower(comment.User.Name)
+		state.active.Insert(user)
+		if !lp.allowAuthor && user == state.author {
+			continue
+		}
+		switch {
+		case approveCancelRegex.MatchString(body):
+			state.approvedBy.Delete(user)
+		case approveRegex.MatchString(body):
+			state.approvedBy.Insert(user)
 		}
 	}
-	if !reviewers.Has(strings.ToLower(e.User.Name)) {
-		resp := "adding APPROVE is restricted to approvers and reviewers in OWNERS files."
-		return lp.issueClient.CreateIssueComment(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.GitIssueComment{
-			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
-		})
+	return state, nil
+}
+
+// reviewLoad returns the number of open pull requests assigned to each lower cased user.
+func (lp *approvePlugin) reviewLoad(ctx context.Context, repo plugins.GitRepo) (map[string]int, error) {
+	load := map[string]int{}
+	if lp.searchClient == nil {
+		return load, nil
 	}
-	if approveCancelMatch {
-		return lp.issueClient.RemoveLabel(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.Label{Name: "approved"})
+	prs, err := lp.searchClient.SearchPR(ctx, repo, plugins.PullRequestStateOpen)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		for _, u := range pr.Assignees {
+			load[strings.ToLower(u.Name)]++
+		}
 	}
-	return lp.issueClient.AddLabel(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, []plugins.Label{{Name: "approved"}})
+	return load, nil
 }
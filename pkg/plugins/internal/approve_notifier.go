@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// approveNotifierMarker identifies the notifier comment of the approve plugin, which is
+// edited in place whenever the approval state changes.
+const approveNotifierMarker = "<!-- kuilei:approval-notifier -->"
+
+// approvalState is the approval state of a pull request.
+type approvalState struct {
+	// author is the lower cased author of the pull request
+	author string
+	// files are the changed files
+	files []string
+	// owners maps a file to the key of its approval unit
+	owners map[string]string
+	// units are the approval units keyed by approvalUnitKey
+	units map[string]*approvalUnit
+	// approvedBy are the lower cased users who approved
+	approvedBy sets.String
+	// active are the lower cased users who commented on the pull request
+	active sets.String
+	// notifier is the notifier comment, nil if it is not created yet
+	notifier *plugins.GitIssueComment
+}
+
+// approvalUnit is a group of changed files under the same OWNERS directory with the same
+// approvers, one approval covers all of them. Files matched by different filters of an
+// OWNERS file are in different units.
+type approvalUnit struct {
+	// dir is the directory of the nearest OWNERS file
+	dir string
+	// approvers are the lower cased approvers, including the parent and the filter ones
+	approvers sets.String
+	// files are the changed files of the unit
+	files []string
+}
+
+// approvalUnitKey returns the key of the unit of files under dir with the lower cased approvers.
+func approvalUnitKey(dir string, approvers sets.String) string {
+	return dir + "\x00" + strings.Join(approvers.List(), ",")
+}
+
+// addFile adds a changed file to the unit of its owners.
+func (s *approvalState) addFile(file string, owners plugins.OwnersConfiguration) {
+	approvers := sets.NewString()
+	for _, name := range owners.Approvers {
+		approvers.Insert(strings.ToLower(name))
+	}
+	key := approvalUnitKey(owners.Path, approvers)
+	unit, ok := s.units[key]
+	if !ok {
+		unit = &approvalUnit{dir: owners.Path, approvers: approvers}
+		s.units[key] = unit
+	}
+	unit.files = append(unit.files, file)
+	s.files = append(s.files, file)
+	s.owners[file] = key
+}
+
+// allApprovers returns all approvers of the changed files.
+func (s *approvalState) allApprovers() sets.String {
+	out := sets.NewString()
+	for _, unit := range s.units {
+		out = out.Union(unit.approvers)
+	}
+	return out
+}
+
+// unitKeys returns the sorted keys of all units, which are ordered by their directories.
+func (s *approvalState) unitKeys() []string {
+	keys := make([]string, 0, len(s.units))
+	for key := range s.units {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unapprovedUnits returns the sorted keys of the units which are not approved yet.
+func (s *approvalState) unapprovedUnits() []string {
+	var keys []string
+	for _, key := range s.unitKeys() {
+		if s.units[key].approvers.Intersection(s.approvedBy).Len() == 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// unapprovedFiles returns the changed files which are not approved yet.
+func (s *approvalState) unapprovedFiles() []string {
+	unapproved := sets.NewString(s.unapprovedUnits()...)
+	var files []string
+	for _, file := range s.files {
+		if unapproved.Has(s.owners[file]) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// suggestApprovers greedily picks a small set of approvers who together cover all unapproved
+// units. Approvers who are active in the pull request are preferred, and
+// approvers with a high review load are avoided.
+func (s *approvalState) suggestApprovers(load map[string]int) []string {
+	uncovered := sets.NewString(s.unapprovedUnits()...)
+	candidates := sets.NewString()
+	for _, key := range uncovered.UnsortedList() {
+		candidates = candidates.Union(s.units[key].approvers)
+	}
+	candidates.Delete(s.author)
+
+	var suggested []string
+	for uncovered.Len() > 0 {
+		best, bestScore := "", 0.0
+		for _, candidate := range candidates.List() {
+			covered := 0
+			for _, key := range uncovered.UnsortedList() {
+				if s.units[key].approvers.Has(candidate) {
+					covered++
+				}
+			}
+			score := float64(covered) / float64(1+load[candidate])
+			if s.active.Has(candidate) {
+				score *= 2
+			}
+			if score > bestScore {
+				best, bestScore = candidate, score
+			}
+		}
+		if best == "" {
+			break
+		}
+		suggested = append(suggested, best)
+		candidates.Delete(best)
+		for _, key := range uncovered.UnsortedList() {
+			if s.units[key].approvers.Has(best) {
+				uncovered.Delete(key)
+			}
+		}
+	}
+	return suggested
+}
+
+// notification renders the body of the notifier comment.
+func (s *approvalState) notification(suggested []string) string {
+	var b strings.Builder
+	b.WriteString(approveNotifierMarker + "\n")
+	if len(s.unapprovedUnits()) == 0 {
+		b.WriteString("[APPROVALNOTIFIER] This PR is **APPROVED**\n\n")
+	} else {
+		b.WriteString("[APPROVALNOTIFIER] This PR is **NOT APPROVED**\n\n")
+	}
+	approvedBy := s.approvedBy.Intersection(s.allApprovers()).List()
+	if len(approvedBy) > 0 {
+		fmt.Fprintf(&b, "This pull-request has been approved by: %s\n", emphasize(approvedBy))
+	}
+	if len(suggested) > 0 {
+		fmt.Fprintf(&b, "To complete the pull request process, please ask for approval from %s.\n", emphasize(suggested))
+	}
+	b.WriteString("\nApprovers can indicate their approval by writing `/approve` in a comment\n")
+	b.WriteString("Approvers can cancel approval by writing `/approve cancel` in a comment\n")
+
+	keys := s.unitKeys()
+	unitsOf := map[string]int{}
+	for _, key := range keys {
+		unitsOf[s.units[key].dir]++
+	}
+	b.WriteString("\n<details>\n\nNeeds approval from an approver in each of these files:\n\n")
+	for _, key := range keys {
+		unit := s.units[key]
+		owners := path.Join(unit.dir, "OWNERS")
+		// Name the files if the filters of the OWNERS file split them into several units
+		files := ""
+		if unitsOf[unit.dir] > 1 {
+			files = fmt.Sprintf(" (`%s`)", strings.Join(unit.files, "`, `"))
+		}
+		if by := unit.approvers.Intersection(s.approvedBy).List(); len(by) > 0 {
+			fmt.Fprintf(&b, "- ~~[%s]~~%s [%s]\n", owners, files, strings.Join(by, ","))
+		} else {
+			fmt.Fprintf(&b, "- **[%s]**%s\n", owners, files)
+		}
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
+
+func emphasize(users []string) string {
+	var out []string
+	for _, u := range users {
+		out = append(out, "*"+u+"*")
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,164 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin approve", func() {
+	var (
+		addedLabels  []plugins.Label
+		removedLabel plugins.Label
+		history      []plugins.GitIssueComment
+		prLabels     []plugins.Label
+		files        []plugins.GitCommitFile
+	)
+	plugin := plugins.GetGitCommentPlugin("approve", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comment.ID, comment.User = len(history)+1, plugins.GitUser{Name: "kuilei-bot"}
+				history = append(history, comment)
+				return nil
+			},
+			"EditIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				history[comment.ID-1].Body = comment.Body
+				return nil
+			},
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, l []plugins.Label) error {
+				addedLabels = l
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, l plugins.Label) error {
+				removedLabel = l
+				return nil
+			},
+			"ListIssueComments": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) ([]plugins.GitIssueComment, error) {
+				return append([]plugins.GitIssueComment{}, history...), nil
+			},
+		}),
+		GitPRClient: mock.FakeGitPRClient(
+			func(ctx context.Context, gr plugins.GitRepo, gpr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return files, nil
+			},
+			func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{Number: 11, User: plugins.GitUser{Name: "author"}, Labels: prLabels}, nil
+			},
+			func(ctx context.Context, repo plugins.GitRepo, number int, method string) error {
+				return nil
+			},
+		),
+		GitSearchClient: mock.FakeSearchClient(map[string]interface{}{
+			"SearchPR": func(ctx context.Context, repo plugins.GitRepo, state string) ([]plugins.GitPullRequestSearchResult, error) {
+				busy := plugins.GitPullRequestSearchResult{GitPullRequest: plugins.GitPullRequest{
+					Assignees: []plugins.GitUser{{Name: "root"}},
+				}}
+				return []plugins.GitPullRequestSearchResult{busy, busy, busy}, nil
+			},
+		}),
+		OwnersClient: mock.FakeOwnerClient(func(owner, repo, file string) (plugins.OwnersConfiguration, error) {
+			switch file {
+			case "pkg/foo.go":
+				return plugins.OwnersConfiguration{
+					Path: "pkg", Approvers: []string{"pkg-approver", "root"}, Reviewers: []string{"pkg-reviewer"},
+				}, nil
+			case "api/types.go":
+				// go-approver comes from a `\.go$` filter of api/OWNERS
+				return plugins.OwnersConfiguration{Path: "api", Approvers: []string{"api-approver", "go-approver"}}, nil
+			case "api/README.md":
+				return plugins.OwnersConfiguration{Path: "api", Approvers: []string{"api-approver"}}, nil
+			}
+			return plugins.OwnersConfiguration{Path: "docs", Approvers: []string{"docs-approver", "root"}}, nil
+		}),
+		LoggerClient: mock.FakeLoggerClient(),
+		BotClient:    mock.FakeBotClient("kuilei-bot"),
+	})
+	approve := func(user, body string) {
+		history = append(history, plugins.GitIssueComment{ID: len(history) + 1, Body: body, User: plugins.GitUser{Name: user}})
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 11, Body: body, User: plugins.GitUser{Name: user}},
+			Action:     plugins.GitCommentActionCreated,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		addedLabels, removedLabel, history, prLabels = nil, plugins.Label{}, nil, nil
+		files = []plugins.GitCommitFile{{Path: "pkg/foo.go"}, {Path: "docs/README.md"}}
+	})
+
+	It("Should not accept approve of reviewers and authors", func() {
+		approve("pkg-reviewer", "/approve")
+		approve("author", "/approve")
+		Expect(addedLabels).Should(BeEmpty())
+		Expect(history).Should(HaveLen(4))
+		Expect(history[1].Body).Should(ContainSubstring("restricted to approvers"))
+		Expect(history[3].Body).Should(ContainSubstring("your own PR"))
+	})
+	It("Should create the notifier when the PR is opened", func() {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 11, User: plugins.GitUser{Name: "author"}},
+			Action:     "opened",
+		})).Should(Succeed())
+		Expect(history).Should(HaveLen(1))
+		Expect(history[0].Body).Should(ContainSubstring("This PR is **NOT APPROVED**"))
+		Expect(history[0].Body).Should(ContainSubstring("- **[docs/OWNERS]**\n- **[pkg/OWNERS]**\n"))
+		// root covers both files but is busy
+		Expect(history[0].Body).Should(ContainSubstring("please ask for approval from *docs-approver*, *pkg-approver*."))
+	})
+	It("Should add approved when all files are covered", func() {
+		approve("pkg-approver", "/approve")
+		Expect(addedLabels).Should(BeEmpty())
+		Expect(history).Should(HaveLen(2))
+		notifier := history[1].Body
+		Expect(notifier).Should(ContainSubstring("This PR is **NOT APPROVED**"))
+		Expect(notifier).Should(ContainSubstring("approved by: *pkg-approver*"))
+		Expect(notifier).Should(ContainSubstring("- **[docs/OWNERS]**\n- ~~[pkg/OWNERS]~~ [pkg-approver]\n"))
+		Expect(notifier).Should(ContainSubstring("please ask for approval from *docs-approver*."))
+
+		approve("Docs-Approver", "/approve")
+		Expect(addedLabels).Should(Equal([]plugins.Label{{Name: "approved"}}))
+		// The notifier is edited in place
+		Expect(history).Should(HaveLen(3))
+		Expect(history[1].Body).Should(ContainSubstring("This PR is **APPROVED**"))
+		Expect(history[1].Body).ShouldNot(ContainSubstring("please ask for approval"))
+	})
+	It("Should not edit a notifier spoofed by users", func() {
+		history = append(history, plugins.GitIssueComment{
+			ID: 1, Body: "<!-- kuilei:approval-notifier -->\nThis PR is **APPROVED**", User: plugins.GitUser{Name: "author"},
+		})
+		approve("pkg-approver", "/approve")
+		Expect(history).Should(HaveLen(3))
+		Expect(history[0].Body).Should(ContainSubstring("This PR is **APPROVED**"))
+		Expect(history[2].User.Name).Should(Equal("kuilei-bot"))
+		Expect(history[2].Body).Should(ContainSubstring("This PR is **NOT APPROVED**"))
+	})
+	It("Should add approved by an approver of all files", func() {
+		approve("root", "/approve")
+		Expect(addedLabels).Should(Equal([]plugins.Label{{Name: "approved"}}))
+	})
+	It("Should remove approved when the coverage is cancelled", func() {
+		approve("pkg-approver", "/approve")
+		approve("docs-approver", "/approve")
+		prLabels = []plugins.Label{{Name: "approved"}}
+		addedLabels = nil
+		approve("docs-approver", "/approve cancel")
+		Expect(removedLabel).Should(Equal(plugins.Label{Name: "approved"}))
+		Expect(addedLabels).Should(BeEmpty())
+		Expect(history[1].Body).Should(ContainSubstring("This PR is **NOT APPROVED**"))
+	})
+	It("Should require approvers of each file matched by different filters", func() {
+		files = []plugins.GitCommitFile{{Path: "api/types.go"}, {Path: "api/README.md"}}
+		approve("go-approver", "/approve")
+		Expect(addedLabels).Should(BeEmpty())
+		notifier := history[1].Body
+		Expect(notifier).Should(ContainSubstring("This PR is **NOT APPROVED**"))
+		Expect(notifier).Should(ContainSubstring("- **[api/OWNERS]** (`api/README.md`)\n"))
+		Expect(notifier).Should(ContainSubstring("- ~~[api/OWNERS]~~ (`api/types.go`) [go-approver]\n"))
+
+		approve("api-approver", "/approve")
+		Expect(addedLabels).Should(Equal([]plugins.Label{{Name: "approved"}}))
+	})
+})
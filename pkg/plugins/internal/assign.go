@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var (
+	assignRegex = regexp.MustCompile(`(?mi)^/(un)?assign((?:[ \t]+@?[-\w]+)*)[ \t]*$`)
+	ccRegex     = regexp.MustCompile(`(?mi)^/(un)?cc((?:[ \t]+@?[-\w]+)*)[ \t]*$`)
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("assign", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &assignPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			repoClient:  cs.GitRepoClient,
+		}
+		return plugin
+	})
+}
+
+type assignPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	repoClient  plugins.GitRepoClient
+}
+
+func (ap *assignPlugin) Name() string {
+	return "assign"
+}
+
+func (ap *assignPlugin) Description() string {
+	return "Assigns or unassigns users to an issue or pull request, and requests or unrequests reviews of a pull request."
+}
+
+func (ap *assignPlugin) Usage() string {
+	return "/[un]assign [@user ...], /[un]cc [@user ...]"
+}
+
+func (ap *assignPlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (ap *assignPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if e.Action != plugins.GitCommentActionCreated {
+		return nil
+	}
+	bodyClean := plugins.CleanMarkdownComments(e.Body)
+	issue := plugins.GitIssue{Number: e.Number}
+
+	var replies []string
+	for _, match := range assignRegex.FindAllStringSubmatch(bodyClean, -1) {
+		logins := parseLogins(match[2], e.User.Name)
+		if match[1] != "" {
+			if err := ap.issueClient.RemoveAssignees(ctx, e.Repo, issue, logins); err != nil {
+				return err
+			}
+			continue
+		}
+		allowed, refused, err := ap.splitCollaborators(ctx, e.Repo, logins)
+		if err != nil {
+			return err
+		}
+		if len(refused) > 0 {
+			replies = append(replies, fmt.Sprintf("GitHub didn't allow me to assign the following users: %s.\n\n"+
+				"Note that only collaborators of %s/%s can be assigned.", strings.Join(refused, ", "), e.Repo.Owner.Name, e.Repo.Name))
+		}
+		if len(allowed) == 0 {
+			continue
+		}
+		if err := ap.issueClient.AddAssignees(ctx, e.Repo, issue, allowed); err != nil {
+			return err
+		}
+	}
+	for _, match := range ccRegex.FindAllStringSubmatch(bodyClean, -1) {
+		if !e.IsPR {
+			break
+		}
+		logins := parseLogins(match[2], e.User.Name)
+		if match[1] != "" {
+			if err := ap.prClient.RemoveReviewers(ctx, e.Repo, e.Number, logins); err != nil {
+				return err
+			}
+			continue
+		}
+		allowed, refused, err := ap.splitCollaborators(ctx, e.Repo, logins)
+		if err != nil {
+			return err
+		}
+		if len(refused) > 0 {
+			replies = append(replies, fmt.Sprintf("GitHub didn't allow me to request PR reviews from the following users: %s.\n\n"+
+				"Note that only collaborators of %s/%s can review this PR.", strings.Join(refused, ", "), e.Repo.Owner.Name, e.Repo.Name))
+		}
+		if len(allowed) == 0 {
+			continue
+		}
+		if err := ap.prClient.RequestReviewers(ctx, e.Repo, e.Number, allowed); err != nil {
+			return err
+		}
+	}
+	if len(replies) == 0 {
+		return nil
+	}
+	return ap.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+		Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, strings.Join(replies, "\n\n")),
+	})
+}
+
+// splitCollaborators splits logins into the collaborators of the repo and the others.
+func (ap *assignPlugin) splitCollaborators(ctx context.Context, repo plugins.GitRepo, logins []string) (collaborators, others []string, err error) {
+	for _, login := range logins {
+		ok, err := ap.repoClient.IsCollaborator(ctx, repo, login)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			collaborators = append(collaborators, login)
+		} else {
+			others = append(others, login)
+		}
+	}
+	return collaborators, others, nil
+}
+
+// parseLogins returns the logins in the arguments of a command, the commenter if there are none.
+func parseLogins(args, commenter string) []string {
+	var logins []string
+	for _, field := range strings.Fields(args) {
+		logins = append(logins, strings.TrimPrefix(field, "@"))
+	}
+	if len(logins) == 0 {
+		return []string{commenter}
+	}
+	return logins
+}
@@ -0,0 +1,93 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin assign", func() {
+	var (
+		assigned, unassigned, requested, unrequested []string
+		comments                                     []plugins.GitIssueComment
+	)
+	plugin := plugins.GetGitCommentPlugin("assign", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+			"AddAssignees": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, logins []string) error {
+				assigned = append(assigned, logins...)
+				return nil
+			},
+			"RemoveAssignees": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, logins []string) error {
+				unassigned = append(unassigned, logins...)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"RequestReviewers": func(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+				requested = append(requested, reviewers...)
+				return nil
+			},
+			"RemoveReviewers": func(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+				unrequested = append(unrequested, reviewers...)
+				return nil
+			},
+		}),
+		GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+			"IsCollaborator": func(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+				return login != "stranger", nil
+			},
+		}),
+	})
+	comment := func(isPR bool, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: isPR, Number: 11, Body: body, User: plugins.GitUser{Name: "foo"}},
+			Action:     plugins.GitCommentActionCreated,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		assigned, unassigned, requested, unrequested, comments = nil, nil, nil, nil, nil
+	})
+
+	It("Should assign the commenter", func() {
+		comment(false, "/assign")
+		Expect(assigned).Should(Equal([]string{"foo"}))
+		comment(false, "/unassign")
+		Expect(unassigned).Should(Equal([]string{"foo"}))
+	})
+	It("Should assign users", func() {
+		comment(false, "/assign @alice bob\n/unassign @carol")
+		Expect(assigned).Should(Equal([]string{"alice", "bob"}))
+		Expect(unassigned).Should(Equal([]string{"carol"}))
+		Expect(comments).Should(BeEmpty())
+	})
+	It("Should reply if users cannot be assigned", func() {
+		comment(false, "/assign @alice @stranger")
+		Expect(assigned).Should(Equal([]string{"alice"}))
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("didn't allow me to assign the following users: stranger"))
+	})
+	It("Should request reviews of pull requests", func() {
+		comment(true, "/cc @alice @stranger\n/uncc @bob")
+		Expect(requested).Should(Equal([]string{"alice"}))
+		Expect(unrequested).Should(Equal([]string{"bob"}))
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("request PR reviews from the following users: stranger"))
+	})
+	It("Should not request reviews of issues", func() {
+		comment(false, "/cc @alice")
+		Expect(requested).Should(BeEmpty())
+	})
+	It("Should ignore other commands", func() {
+		comment(true, "/assignee @alice\n/ccc")
+		Expect(assigned).Should(BeEmpty())
+		Expect(requested).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var autoCCRegex = regexp.MustCompile(`(?m)^/auto-cc\s*$`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("blunderbuss", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &blunderbussPlugin{
+			issueClient:  cs.GitIssueClient,
+			prClient:     cs.GitPRClient,
+			ownerClient:  cs.OwnersClient,
+			configClient: cs.PluginConfigClient,
+		}
+		return plugin
+	})
+}
+
+type blunderbussPlugin struct {
+	issueClient  plugins.GitIssueClient
+	prClient     plugins.GitPRClient
+	ownerClient  plugins.OwnersClient
+	configClient plugins.PluginConfigClient
+
+	reviewerCount int
+	skipBusy      bool
+}
+
+func (bp *blunderbussPlugin) Name() string {
+	return "blunderbuss"
+}
+
+func (bp *blunderbussPlugin) Description() string {
+	return "Requests reviews from reviewers in OWNERS files of the changed files when a pull request is opened."
+}
+
+func (bp *blunderbussPlugin) Usage() string {
+	return "/auto-cc"
+}
+
+func (bp *blunderbussPlugin) BindFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&bp.reviewerCount, "reviewer-count", 2, "Number of reviewers to request")
+	flags.BoolVar(&bp.skipBusy, "skip-busy", false, "Whether skip the busy users in the blunderbuss section of the config")
+}
+
+func (bp *blunderbussPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	autoCC := false
+	switch {
+	case e.Action == "opened":
+	case e.Action == plugins.GitCommentActionCreated && autoCCRegex.MatchString(plugins.CleanMarkdownComments(e.Body)):
+		autoCC = true
+	default:
+		return nil
+	}
+
+	pr, err := bp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	// Only fill up the requested reviewers of new pull requests, /auto-cc always requests more
+	count := bp.reviewerCount
+	if !autoCC {
+		count -= len(pr.RequestedReviewers)
+	}
+	if count <= 0 {
+		return nil
+	}
+
+	excluded := sets.NewString(strings.ToLower(pr.User.Name))
+	for _, u := range pr.RequestedReviewers {
+		excluded.Insert(strings.ToLower(u.Name))
+	}
+	if bp.skipBusy {
+		cfg, err := bp.configClient.GetConfig(e.Repo.Owner.Name, e.Repo.Name)
+		if err != nil {
+			return err
+		}
+		for _, name := range cfg.Blunderbuss.BusyUsers {
+			excluded.Insert(strings.ToLower(name))
+		}
+	}
+	weights, err := bp.reviewerWeights(ctx, e, excluded)
+	if err != nil {
+		return err
+	}
+	reviewers := selectWeighted(weights, count)
+	if len(reviewers) == 0 {
+		if !autoCC {
+			return nil
+		}
+		resp := "no reviewers are available in OWNERS files of the changed files."
+		return bp.issueClient.CreateIssueComment(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+	return bp.prClient.RequestReviewers(ctx, e.Repo, e.Number, reviewers)
+}
+
+// reviewerWeights returns the number of changed lines each reviewer owns.
+func (bp *blunderbussPlugin) reviewerWeights(ctx context.Context, e plugins.GitCommentEvent, excluded sets.String) (map[string]int, error) {
+	files, err := bp.prClient.ListFiles(ctx, e.Repo, plugins.GitPullRequest{Number: e.Number})
+	if err != nil {
+		return nil, err
+	}
+	weights := map[string]int{}
+	for _, file := range files {
+		owners, err := bp.ownerClient.GetOwners(e.Repo.Owner.Name, e.Repo.Name, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range owners.Reviewers {
+			if excluded.Has(strings.ToLower(name)) {
+				continue
+			}
+			weights[name] += file.Changes
+		}
+	}
+	return weights, nil
+}
+
+// selectWeighted randomly selects up to count distinct candidates, the chance of a candidate
+// is proportional to its weight. Every candidate has a weight of at least 1.
+func selectWeighted(weights map[string]int, count int) []string {
+	candidates := make([]string, 0, len(weights))
+	for name := range weights {
+		candidates = append(candidates, name)
+	}
+	sort.Strings(candidates)
+
+	var selected []string
+	for len(selected) < count && len(candidates) > 0 {
+		total := 0
+		for _, name := range candidates {
+			total += weightOf(weights, name)
+		}
+		n := rand.Intn(total)
+		for i, name := range candidates {
+			if n -= weightOf(weights, name); n < 0 {
+				selected = append(selected, name)
+				candidates = append(candidates[:i], candidates[i+1:]...)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func weightOf(weights map[string]int, name string) int {
+	if weights[name] < 1 {
+		return 1
+	}
+	return weights[name]
+}
@@ -0,0 +1,106 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin blunderbuss", func() {
+	var (
+		requested [][]string
+		comments  []plugins.GitIssueComment
+		pr        plugins.GitPullRequest
+		files     []plugins.GitCommitFile
+	)
+	clientSets := plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, in plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return files, nil
+			},
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return pr, nil
+			},
+			"RequestReviewers": func(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+				requested = append(requested, reviewers)
+				return nil
+			},
+		}),
+		OwnersClient: mock.FakeOwnerClient(func(owner, repo, file string) (plugins.OwnersConfiguration, error) {
+			switch file {
+			case "pkg/foo.go":
+				return plugins.OwnersConfiguration{Reviewers: []string{"alice", "bob"}}, nil
+			case "docs/README.md":
+				return plugins.OwnersConfiguration{Reviewers: []string{"carol", "author"}}, nil
+			}
+			return plugins.OwnersConfiguration{}, nil
+		}),
+		PluginConfigClient: mock.FakeConfigClient(func(owner, repo string) (plugins.Configuration, error) {
+			return plugins.Configuration{Blunderbuss: plugins.BlunderbussConfiguration{BusyUsers: []string{"Bob"}}}, nil
+		}),
+		LoggerClient: mock.FakeLoggerClient(),
+	}
+	opened := plugins.GitCommentEvent{
+		GitComment: plugins.GitComment{IsPR: true, Number: 11, User: plugins.GitUser{Name: "author"}},
+		Action:     "opened",
+	}
+	autoCC := plugins.GitCommentEvent{
+		GitComment: plugins.GitComment{IsPR: true, Number: 11, Body: "/auto-cc", User: plugins.GitUser{Name: "foo"}},
+		Action:     plugins.GitCommentActionCreated,
+	}
+	BeforeEach(func() {
+		requested, comments = nil, nil
+		pr = plugins.GitPullRequest{Number: 11, User: plugins.GitUser{Name: "author"}}
+		files = []plugins.GitCommitFile{{Path: "pkg/foo.go", Changes: 100}, {Path: "docs/README.md", Changes: 1}}
+	})
+
+	It("Should request reviewers except the author and busy users", func() {
+		plugin := plugins.GetGitCommentPlugin("blunderbuss", clientSets, "--skip-busy")
+		Expect(plugin.Do(context.TODO(), opened)).Should(Succeed())
+		Expect(requested).Should(HaveLen(1))
+		Expect(requested[0]).Should(ConsistOf("alice", "carol"))
+	})
+	It("Should not request reviewers if enough reviewers are requested", func() {
+		plugin := plugins.GetGitCommentPlugin("blunderbuss", clientSets)
+		pr.RequestedReviewers = []plugins.GitUser{{Name: "alice"}, {Name: "bob"}}
+		Expect(plugin.Do(context.TODO(), opened)).Should(Succeed())
+		Expect(requested).Should(BeEmpty())
+
+		Expect(plugin.Do(context.TODO(), autoCC)).Should(Succeed())
+		Expect(requested).Should(Equal([][]string{{"carol"}}))
+	})
+	It("Should prefer reviewers owning more changed lines", func() {
+		plugin := plugins.GetGitCommentPlugin("blunderbuss", clientSets, "--skip-busy", "--reviewer-count=1")
+		for i := 0; i < 200; i++ {
+			Expect(plugin.Do(context.TODO(), autoCC)).Should(Succeed())
+		}
+		alice := 0
+		for _, reviewers := range requested {
+			Expect(reviewers).Should(HaveLen(1))
+			if reviewers[0] == "alice" {
+				alice++
+			}
+		}
+		Expect(alice).Should(BeNumerically(">", 150))
+	})
+	It("Should reply if no reviewers are available", func() {
+		plugin := plugins.GetGitCommentPlugin("blunderbuss", clientSets)
+		files = []plugins.GitCommitFile{{Path: "README.md", Changes: 1}}
+		Expect(plugin.Do(context.TODO(), opened)).Should(Succeed())
+		Expect(comments).Should(BeEmpty())
+		Expect(plugin.Do(context.TODO(), autoCC)).Should(Succeed())
+		Expect(requested).Should(BeEmpty())
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("no reviewers are available"))
+	})
+})
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var cherryPickRegex = regexp.MustCompile(`(?m)^/cherry-?pick[ \t]+(\S+)[ \t]*$`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("cherry-pick", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &cherryPickPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			localClient: cs.GitLocalClient,
+			repoClient:  cs.GitRepoClient,
+			orgClient:   cs.GitOrgClient,
+			logger:      cs.LoggerClient,
+		}
+		return plugin
+	})
+}
+
+type cherryPickPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	localClient plugins.GitLocalClient
+	repoClient  plugins.GitRepoClient
+	orgClient   plugins.GitOrgClient
+	logger      plugins.LoggerClient
+}
+
+func (cp *cherryPickPlugin) Name() string {
+	return "cherry-pick"
+}
+
+func (cp *cherryPickPlugin) Description() string {
+	return "Cherry-picks a pull request into another branch once it is merged, and opens a pull request for it."
+}
+
+func (cp *cherryPickPlugin) Usage() string {
+	return "/cherry-pick <branch>"
+}
+
+func (cp *cherryPickPlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (cp *cherryPickPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "closed":
+		return cp.onMerged(ctx, e)
+	case plugins.GitCommentActionCreated:
+	default:
+		return nil
+	}
+	matches := cherryPickRegex.FindAllStringSubmatch(plugins.CleanMarkdownComments(e.Body), -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	allowed, err := cp.isAllowed(ctx, e.Repo, e.User.Name, e.AuthorAssociation, e.IssueAuthor.Name)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		resp := "cherry-pick is restricted to members, collaborators and the author of the pull request."
+		return cp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+
+	pr, err := cp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		target := match[1]
+		if pr.Merged {
+			if err := cp.cherryPick(ctx, e.Repo, pr, target); err != nil {
+				return err
+			}
+			continue
+		}
+		resp := fmt.Sprintf("once this PR is merged, I will cherry-pick it into `%s`.", target)
+		if err := cp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onMerged cherry-picks the pull request into the branches requested in its comments.
+func (cp *cherryPickPlugin) onMerged(ctx context.Context, e plugins.GitCommentEvent) error {
+	pr, err := cp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	if !pr.Merged {
+		return nil
+	}
+	comments, err := cp.issueClient.ListIssueComments(ctx, e.Repo, plugins.GitIssue{Number: e.Number})
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, comment := range comments {
+		matches := cherryPickRegex.FindAllStringSubmatch(plugins.CleanMarkdownComments(comment.Body), -1)
+		if len(matches) == 0 {
+			continue
+		}
+		allowed, err := cp.isAllowed(ctx, e.Repo, comment.User.Name, comment.AuthorAssociation, pr.User.Name)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			continue
+		}
+		for _, match := range matches {
+			if target := match[1]; !seen[target] {
+				seen[target] = true
+				if err := cp.cherryPick(ctx, e.Repo, pr, target); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cherryPick applies the commits of a merged pull request onto target, and opens a pull request for it.
+func (cp *cherryPickPlugin) cherryPick(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest, target string) error {
+	issue := plugins.GitIssue{Number: pr.Number}
+	branch := fmt.Sprintf("cherry-pick-%d-to-%s", pr.Number, target)
+	conflicts, err := cp.localClient.CherryPick(ctx, repo, plugins.GitCherryPickOptions{
+		Onto:     target,
+		Refs:     []string{fmt.Sprintf("refs/pull/%d/head", pr.Number), "refs/heads/" + pr.Base.Ref},
+		MergeSHA: pr.MergeSHA,
+		HeadSHA:  pr.Head.SHA,
+		Branch:   branch,
+	})
+	if err != nil {
+		// The output of git is not posted, it may contain credentials
+		cp.logger.GetLogger().Error(err, "Failed to cherry-pick", "number", pr.Number, "target", target)
+		return cp.issueClient.CreateIssueComment(ctx, repo, issue, plugins.GitIssueComment{
+			Body: fmt.Sprintf("Failed to cherry-pick #%d into `%s`, the branch may not exist or the push was rejected. "+
+				"Please cherry-pick it manually.", pr.Number, target),
+		})
+	}
+	if len(conflicts) > 0 {
+		body := fmt.Sprintf("#%d failed to apply on top of branch `%s`, the following files conflict:\n", pr.Number, target)
+		for _, file := range conflicts {
+			body += fmt.Sprintf("- `%s`\n", file)
+		}
+		body += "\nPlease cherry-pick it manually."
+		return cp.issueClient.CreateIssueComment(ctx, repo, issue, plugins.GitIssueComment{Body: body})
+	}
+
+	number, err := cp.prClient.CreatePR(ctx, repo, plugins.GitPullRequest{
+		Title: fmt.Sprintf("[%s] %s", target, pr.Title),
+		Body:  fmt.Sprintf("This is an automated cherry-pick of #%d\n\n/assign %s", pr.Number, pr.User.Name),
+		Head:  plugins.GitBranch{Ref: branch},
+		Base:  plugins.GitBranch{Ref: target},
+	})
+	if err != nil {
+		return err
+	}
+	return cp.issueClient.CreateIssueComment(ctx, repo, issue, plugins.GitIssueComment{
+		Body: fmt.Sprintf("New pull request created for the cherry-pick into `%s`: #%d", target, number),
+	})
+}
+
+// isAllowed returns true if the user is trusted or the author of the pull request.
+func (cp *cherryPickPlugin) isAllowed(ctx context.Context, repo plugins.GitRepo, login, association, author string) (bool, error) {
+	if strings.EqualFold(login, author) {
+		return true, nil
+	}
+	return plugins.IsTrusted(ctx, cp.orgClient, cp.repoClient, repo, login, association)
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var (
+	closeRegex  = regexp.MustCompile(`(?mi)^/close(?:[ \t]+(not-planned))?[ \t]*$`)
+	reopenRegex = regexp.MustCompile(`(?mi)^/reopen[ \t]*$`)
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("close", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &closePlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			ownerClient: cs.OwnersClient,
+		}
+		return plugin
+	})
+}
+
+type closePlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	ownerClient plugins.OwnersClient
+}
+
+func (cp *closePlugin) Name() string {
+	return "close"
+}
+
+func (cp *closePlugin) Description() string {
+	return "Closes or reopens an issue or pull request."
+}
+
+func (cp *closePlugin) Usage() string {
+	return "/close [not-planned], /reopen"
+}
+
+func (cp *closePlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (cp *closePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if e.Action != plugins.GitCommentActionCreated {
+		return nil
+	}
+	bodyClean := plugins.CleanMarkdownComments(e.Body)
+	closeMatch := closeRegex.FindStringSubmatch(bodyClean)
+	reopenMatch := reopenRegex.MatchString(bodyClean)
+	closed := strings.EqualFold(e.IssueState, "closed")
+	if (closeMatch == nil || closed) && (!reopenMatch || !closed) {
+		return nil
+	}
+
+	allowed, err := cp.allowed(ctx, e)
+	if err != nil {
+		return err
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	if !allowed {
+		resp := "closing or reopening is restricted to the author, assignees, reviewers and approvers in OWNERS files."
+		return cp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+	if reopenMatch {
+		return cp.issueClient.ReopenIssue(ctx, e.Repo, issue)
+	}
+	reason := plugins.GitIssueCloseReasonCompleted
+	if closeMatch[1] != "" {
+		reason = plugins.GitIssueCloseReasonNotPlanned
+	}
+	if e.IsPR {
+		reason = ""
+	}
+	return cp.issueClient.CloseIssue(ctx, e.Repo, issue, reason)
+}
+
+// allowed returns true if the commenter is the author, an assignee or in OWNERS files.
+func (cp *closePlugin) allowed(ctx context.Context, e plugins.GitCommentEvent) (bool, error) {
+	if strings.EqualFold(e.User.Name, e.IssueAuthor.Name) {
+		return true, nil
+	}
+	for _, assignee := range e.Assignees {
+		if strings.EqualFold(e.User.Name, assignee.Name) {
+			return true, nil
+		}
+	}
+	reviewers, approvers, err := ownersOf(ctx, cp.prClient, cp.ownerClient, e)
+	if err != nil {
+		return false, err
+	}
+	user := strings.ToLower(e.User.Name)
+	return reviewers.Has(user) || approvers.Has(user), nil
+}
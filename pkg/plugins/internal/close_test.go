@@ -0,0 +1,85 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin close", func() {
+	var (
+		closedReasons []string
+		reopened      int
+		comments      []plugins.GitIssueComment
+	)
+	plugin := plugins.GetGitCommentPlugin("close", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+			"CloseIssue": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, reason string) error {
+				closedReasons = append(closedReasons, reason)
+				return nil
+			},
+			"ReopenIssue": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) error {
+				reopened++
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return []plugins.GitCommitFile{{Path: "pkg/foo.go"}}, nil
+			},
+		}),
+		OwnersClient: mock.FakeOwnerClient(func(owner, repo, file string) (plugins.OwnersConfiguration, error) {
+			if file == "pkg/foo.go" {
+				return plugins.OwnersConfiguration{Reviewers: []string{"reviewer"}}, nil
+			}
+			return plugins.OwnersConfiguration{Approvers: []string{"root"}}, nil
+		}),
+	})
+	comment := func(isPR bool, state, user, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{
+				IsPR: isPR, Number: 11, Body: body, IssueState: state,
+				User: plugins.GitUser{Name: user}, IssueAuthor: plugins.GitUser{Name: "author"},
+				Assignees: []plugins.GitUser{{Name: "assignee"}},
+			},
+			Action: plugins.GitCommentActionCreated,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		closedReasons, reopened, comments = nil, 0, nil
+	})
+
+	It("Should close issues", func() {
+		comment(false, "open", "author", "/close")
+		comment(false, "open", "Assignee", "/close not-planned")
+		comment(false, "open", "root", "/close")
+		Expect(closedReasons).Should(Equal([]string{"completed", "not_planned", "completed"}))
+	})
+	It("Should close pull requests without reason", func() {
+		comment(true, "open", "reviewer", "/close not-planned")
+		Expect(closedReasons).Should(Equal([]string{""}))
+	})
+	It("Should reopen closed issues", func() {
+		comment(false, "closed", "author", "/reopen")
+		comment(false, "open", "author", "/reopen")
+		comment(false, "closed", "author", "/close")
+		Expect(reopened).Should(Equal(1))
+		Expect(closedReasons).Should(BeEmpty())
+	})
+	It("Should refuse others", func() {
+		comment(true, "open", "root", "/close")
+		comment(false, "closed", "stranger", "/reopen")
+		Expect(closedReasons).Should(BeEmpty())
+		Expect(reopened).Should(Equal(0))
+		Expect(comments).Should(HaveLen(2))
+		Expect(comments[0].Body).Should(ContainSubstring("closing or reopening is restricted"))
+	})
+})
@@ -1 +1,92 @@
 package internal
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// hasLabel returns true if labels contain name, case insensitively.
+func hasLabel(labels []plugins.Label, name string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBot returns true if user is the App itself or another bot.
+func isBot(ctx context.Context, botClient plugins.BotClient, user plugins.GitUser) (bool, error) {
+	if strings.HasSuffix(strings.ToLower(user.Name), "[bot]") {
+		return true, nil
+	}
+	bot, err := botClient.BotUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(bot.Name, user.Name), nil
+}
+
+// isBotComment returns true if the comment is created by the App and contains marker, so that
+// users can not spoof the comments the App maintains.
+func isBotComment(comment plugins.GitIssueComment, bot plugins.GitUser, marker string) bool {
+	return strings.Contains(comment.Body, marker) && strings.EqualFold(comment.User.Name, bot.Name)
+}
+
+// findBotComment returns the comment of an issue created by the App which contains marker,
+// or nil if there is none.
+func findBotComment(
+	ctx context.Context, issueClient plugins.GitIssueClient, botClient plugins.BotClient,
+	repo plugins.GitRepo, issue plugins.GitIssue, marker string,
+) (*plugins.GitIssueComment, error) {
+	bot, err := botClient.BotUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := issueClient.ListIssueComments(ctx, repo, issue)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		if isBotComment(comments[i], bot, marker) {
+			return &comments[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ownersOf returns the lower cased reviewers and approvers in OWNERS files of the changed
+// files of a pull request, or in the root OWNERS file for an issue.
+func ownersOf(
+	ctx context.Context, prClient plugins.GitPRClient, ownerClient plugins.OwnersClient, e plugins.GitCommentEvent,
+) (reviewers, approvers sets.String, err error) {
+	paths := []string{""}
+	if e.IsPR {
+		files, err := prClient.ListFiles(ctx, e.Repo, plugins.GitPullRequest{Number: e.Number})
+		if err != nil {
+			return nil, nil, err
+		}
+		paths = paths[:0]
+		for _, file := range files {
+			paths = append(paths, file.Path)
+		}
+	}
+	reviewers, approvers = sets.NewString(), sets.NewString()
+	for _, path := range paths {
+		owners, err := ownerClient.GetOwners(e.Repo.Owner.Name, e.Repo.Name, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range owners.Reviewers {
+			reviewers.Insert(strings.ToLower(name))
+		}
+		for _, name := range owners.Approvers {
+			approvers.Insert(strings.ToLower(name))
+		}
+	}
+	return reviewers, approvers, nil
+}
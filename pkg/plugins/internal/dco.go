@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	dcoContext     = "dco"
+	dcoYesLabel    = "dco-signoff: yes"
+	dcoNoLabel     = "dco-signoff: no"
+	dcoMarker      = "<!-- kuilei:dco -->"
+	dcoShortSHALen = 7
+)
+
+var signedOffByRegex = regexp.MustCompile(`(?mi)^Signed-off-by:[ \t]*.*<([^>]+)>[ \t]*$`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("dco", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &dcoPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			repoClient:  cs.GitRepoClient,
+			botClient:   cs.BotClient,
+		}
+	})
+}
+
+// dcoPlugin checks that every commit of a pull request is signed off by its author, which
+// certifies the Developer Certificate of Origin.
+type dcoPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	repoClient  plugins.GitRepoClient
+	botClient   plugins.BotClient
+}
+
+func (dp *dcoPlugin) Name() string {
+	return "dco"
+}
+
+func (dp *dcoPlugin) Description() string {
+	return "Checks that every commit of a pull request has a `Signed-off-by` trailer matching its author email, " +
+		"and sets the `" + dcoContext + "` status and the `" + dcoYesLabel + "` or `" + dcoNoLabel + "` label."
+}
+
+func (dp *dcoPlugin) Usage() string {
+	return "Add 'dco' plugin in configuration located under [.github/kuilei.yml](/.github/kuilei.yml)"
+}
+
+func (dp *dcoPlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (dp *dcoPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "opened", "synchronize", plugins.GitCommentActionReopened:
+	default:
+		return nil
+	}
+	pr, err := dp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	commits, err := dp.prClient.ListCommits(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	var unsigned []plugins.GitCommit
+	for _, commit := range commits {
+		if !isSignedOff(commit) {
+			unsigned = append(unsigned, commit)
+		}
+	}
+
+	status := plugins.GitCommitStatus{
+		Context: dcoContext, State: plugins.GitStatusStateSuccess, Description: "All commits are signed off",
+	}
+	addLabel, removeLabel := dcoYesLabel, dcoNoLabel
+	if len(unsigned) > 0 {
+		status.State = plugins.GitStatusStateFailure
+		status.Description = fmt.Sprintf("%d of %d commits are not signed off", len(unsigned), len(commits))
+		addLabel, removeLabel = dcoNoLabel, dcoYesLabel
+	}
+	if err := dp.repoClient.CreateStatus(ctx, e.Repo, pr.Head.SHA, status); err != nil {
+		return err
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	if hasLabel(pr.Labels, removeLabel) {
+		if err := dp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: removeLabel}); err != nil {
+			return err
+		}
+	}
+	if !hasLabel(pr.Labels, addLabel) {
+		if err := dp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: addLabel}}); err != nil {
+			return err
+		}
+	}
+
+	comment, err := findBotComment(ctx, dp.issueClient, dp.botClient, e.Repo, issue, dcoMarker)
+	if err != nil {
+		return err
+	}
+	if len(unsigned) == 0 {
+		if comment != nil {
+			return dp.issueClient.DeleteIssueComment(ctx, e.Repo, issue, *comment)
+		}
+		return nil
+	}
+	body := fmt.Sprintf("%s\n@%s: Thanks for your pull request. Before it can be merged, every commit must have "+
+		"a `Signed-off-by` trailer matching its author email, which certifies the "+
+		"[Developer Certificate of Origin](https://developercertificate.org/).\n\n"+
+		"The following commits are not signed off:\n\n", dcoMarker, pr.User.Name)
+	for _, commit := range unsigned {
+		sha := commit.Sha
+		if len(sha) > dcoShortSHALen {
+			sha = sha[:dcoShortSHALen]
+		}
+		subject, _, _ := strings.Cut(commit.Message, "\n")
+		body += fmt.Sprintf("- %s %s (author: `%s`)\n", sha, subject, commit.Author.Email)
+	}
+	body += fmt.Sprintf("\nTo sign off the commits, make sure `user.email` of git matches the author email, then run:\n"+
+		"```sh\ngit rebase --signoff HEAD~%d\ngit push --force-with-lease\n```\n", len(commits))
+	switch {
+	case comment == nil:
+		return dp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{Body: body})
+	case comment.Body != body:
+		return dp.issueClient.EditIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{ID: comment.ID, Body: body})
+	}
+	return nil
+}
+
+// isSignedOff returns true if the commit message has a Signed-off-by trailer with the author email.
+func isSignedOff(commit plugins.GitCommit) bool {
+	for _, match := range signedOffByRegex.FindAllStringSubmatch(commit.Message, -1) {
+		if strings.EqualFold(strings.TrimSpace(match[1]), commit.Author.Email) {
+			return true
+		}
+	}
+	return false
+}
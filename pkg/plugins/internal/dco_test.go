@@ -0,0 +1,146 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin dco", func() {
+	var (
+		prLabels []plugins.Label
+		commits  []plugins.GitCommit
+		history  []plugins.GitIssueComment
+		statuses []plugins.GitCommitStatus
+		added    []string
+		removed  []string
+		created  []string
+		edited   []plugins.GitIssueComment
+		deleted  []int
+	)
+	plugin := plugins.GetGitCommentPlugin("dco", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				for _, l := range labels {
+					added = append(added, l.Name)
+				}
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label.Name)
+				return nil
+			},
+			"ListIssueComments": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) ([]plugins.GitIssueComment, error) {
+				return history, nil
+			},
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				created = append(created, comment.Body)
+				return nil
+			},
+			"EditIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				edited = append(edited, comment)
+				return nil
+			},
+			"DeleteIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				deleted = append(deleted, comment.ID)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{
+					Number: number, User: plugins.GitUser{Name: "author"}, Labels: prLabels,
+					Head: plugins.GitBranch{SHA: "head-sha"},
+				}, nil
+			},
+			"ListCommits": func(ctx context.Context, repo plugins.GitRepo, number int) ([]plugins.GitCommit, error) {
+				return commits, nil
+			},
+		}),
+		GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+			"CreateStatus": func(ctx context.Context, repo plugins.GitRepo, ref string, status plugins.GitCommitStatus) error {
+				Expect(ref).Should(Equal("head-sha"))
+				statuses = append(statuses, status)
+				return nil
+			},
+		}),
+		BotClient: mock.FakeBotClient("kuilei[bot]"),
+	})
+	do := func(action plugins.GitCommentEventAction) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 13},
+			Action:     action,
+		})).Should(Succeed())
+	}
+	signed := plugins.GitCommit{
+		Sha:     "1111111111",
+		Message: "Fix foo\n\nSigned-off-by: Alice <Alice@example.com>",
+		Author:  plugins.GitCommitAuthor{Name: "Alice", Email: "alice@example.com"},
+	}
+	BeforeEach(func() {
+		prLabels, commits, history = nil, nil, nil
+		statuses, added, removed, created, edited, deleted = nil, nil, nil, nil, nil, nil
+	})
+
+	It("Should pass signed off commits", func() {
+		commits = []plugins.GitCommit{signed}
+		prLabels = []plugins.Label{{Name: "dco-signoff: no"}}
+		history = []plugins.GitIssueComment{{ID: 5, Body: "<!-- kuilei:dco -->\nold", User: plugins.GitUser{Name: "kuilei[bot]"}}}
+		do("synchronize")
+		Expect(statuses).Should(Equal([]plugins.GitCommitStatus{{
+			Context: "dco", State: plugins.GitStatusStateSuccess, Description: "All commits are signed off",
+		}}))
+		Expect(removed).Should(Equal([]string{"dco-signoff: no"}))
+		Expect(added).Should(Equal([]string{"dco-signoff: yes"}))
+		Expect(deleted).Should(Equal([]int{5}))
+		Expect(created).Should(BeEmpty())
+	})
+
+	It("Should report commits not signed off by their authors", func() {
+		commits = []plugins.GitCommit{signed, {
+			Sha:     "2222222222",
+			Message: "Add bar\n\nSigned-off-by: Bob <bob@example.com>",
+			Author:  plugins.GitCommitAuthor{Name: "Alice", Email: "alice@example.com"},
+		}, {
+			Sha:     "3333333333",
+			Message: "Update docs",
+			Author:  plugins.GitCommitAuthor{Name: "Carol", Email: "carol@example.com"},
+		}}
+		prLabels = []plugins.Label{{Name: "dco-signoff: yes"}}
+		do("opened")
+		Expect(statuses).Should(HaveLen(1))
+		Expect(statuses[0].State).Should(Equal(plugins.GitStatusStateFailure))
+		Expect(statuses[0].Description).Should(Equal("2 of 3 commits are not signed off"))
+		Expect(removed).Should(Equal([]string{"dco-signoff: yes"}))
+		Expect(added).Should(Equal([]string{"dco-signoff: no"}))
+		Expect(created).Should(HaveLen(1))
+		Expect(created[0]).Should(HavePrefix("<!-- kuilei:dco -->\n@author:"))
+		Expect(created[0]).Should(ContainSubstring("- 2222222 Add bar (author: `alice@example.com`)\n"))
+		Expect(created[0]).Should(ContainSubstring("- 3333333 Update docs (author: `carol@example.com`)\n"))
+		Expect(created[0]).ShouldNot(ContainSubstring("1111111"))
+		Expect(created[0]).Should(ContainSubstring("git rebase --signoff HEAD~3"))
+
+		// The comment of the App is edited when the offending commits change, a copy of a user is ignored
+		prLabels = []plugins.Label{{Name: "dco-signoff: no"}}
+		history = []plugins.GitIssueComment{
+			{ID: 4, Body: created[0], User: plugins.GitUser{Name: "author"}},
+			{ID: 6, Body: created[0], User: plugins.GitUser{Name: "kuilei[bot]"}},
+		}
+		commits = commits[:2]
+		do("synchronize")
+		Expect(added).Should(HaveLen(1))
+		Expect(created).Should(HaveLen(1))
+		Expect(edited).Should(HaveLen(1))
+		Expect(edited[0].ID).Should(Equal(6))
+		Expect(edited[0].Body).ShouldNot(ContainSubstring("3333333"))
+	})
+
+	It("Should ignore other events", func() {
+		do(plugins.GitCommentActionCreated)
+		Expect(statuses).Should(BeEmpty())
+	})
+})
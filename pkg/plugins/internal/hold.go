@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const holdLabel = "do-not-merge/hold"
+
+var (
+	holdRegex       = regexp.MustCompile(`(?mi)^/hold[ \t]*$`)
+	holdCancelRegex = regexp.MustCompile(`(?mi)^/(?:hold[ \t]+cancel|unhold|remove-hold)[ \t]*$`)
+)
+
+// Roles of users in the unhold restriction of the hold plugin.
+const (
+	holdRoleAuthor    = "author"
+	holdRoleReviewers = "reviewers"
+	holdRoleApprovers = "approvers"
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("hold", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &holdPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			ownerClient: cs.OwnersClient,
+		}
+		return plugin
+	})
+}
+
+type holdPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	ownerClient plugins.OwnersClient
+
+	unholdRoles []string
+}
+
+func (hp *holdPlugin) Name() string {
+	return "hold"
+}
+
+func (hp *holdPlugin) Description() string {
+	return "Adds or removes the '" + holdLabel + "' label which is typically used to keep a pull request from merging."
+}
+
+func (hp *holdPlugin) Usage() string {
+	return "/hold [cancel]"
+}
+
+func (hp *holdPlugin) BindFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVar(&hp.unholdRoles, "unhold-roles",
+		[]string{holdRoleAuthor, holdRoleReviewers, holdRoleApprovers},
+		"Roles allowed to cancel a hold, any of author, reviewers and approvers")
+}
+
+func (hp *holdPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if e.Action != plugins.GitCommentActionCreated {
+		return nil
+	}
+	bodyClean := plugins.CleanMarkdownComments(e.Body)
+	holdMatch := holdRegex.MatchString(bodyClean)
+	holdCancelMatch := holdCancelRegex.MatchString(bodyClean)
+	if !holdMatch && !holdCancelMatch {
+		return nil
+	}
+
+	roles, err := hp.rolesOf(ctx, e)
+	if err != nil {
+		return err
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	if holdCancelMatch {
+		if !roles.HasAny(hp.unholdRoles...) {
+			resp := "removing the HOLD is restricted to " + strings.Join(hp.unholdRoles, ", ") + " in OWNERS files."
+			return hp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+				Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+			})
+		}
+		return hp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: holdLabel})
+	}
+	if roles.Len() == 0 {
+		resp := "adding HOLD is restricted to the author, reviewers and approvers in OWNERS files."
+		return hp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+	return hp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: holdLabel}})
+}
+
+// rolesOf returns the roles of the commenter.
+func (hp *holdPlugin) rolesOf(ctx context.Context, e plugins.GitCommentEvent) (sets.String, error) {
+	roles := sets.NewString()
+	if strings.EqualFold(e.User.Name, e.IssueAuthor.Name) {
+		roles.Insert(holdRoleAuthor)
+	}
+	reviewers, approvers, err := ownersOf(ctx, hp.prClient, hp.ownerClient, e)
+	if err != nil {
+		return nil, err
+	}
+	user := strings.ToLower(e.User.Name)
+	if reviewers.Has(user) {
+		roles.Insert(holdRoleReviewers)
+	}
+	if approvers.Has(user) {
+		roles.Insert(holdRoleApprovers)
+	}
+	return roles, nil
+}
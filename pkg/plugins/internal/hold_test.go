@@ -0,0 +1,88 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin hold", func() {
+	var (
+		addedLabels  []plugins.Label
+		removedLabel plugins.Label
+		comments     []plugins.GitIssueComment
+	)
+	clientSets := plugins.ClientSets{
+		GitIssueClient: mock.FakeGitIssueClient(
+			func(ctx context.Context, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+			func(ctx context.Context, l []plugins.Label) error {
+				addedLabels = l
+				return nil
+			},
+			func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, l plugins.Label) error {
+				removedLabel = l
+				return nil
+			},
+		),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return []plugins.GitCommitFile{{Path: "pkg/foo.go"}}, nil
+			},
+		}),
+		OwnersClient: mock.FakeOwnerClient(func(owner, repo, file string) (plugins.OwnersConfiguration, error) {
+			if file == "pkg/foo.go" {
+				return plugins.OwnersConfiguration{Reviewers: []string{"reviewer"}, Approvers: []string{"approver"}}, nil
+			}
+			return plugins.OwnersConfiguration{Approvers: []string{"root"}}, nil
+		}),
+	}
+	comment := func(plugin plugins.GitCommentPlugin, isPR bool, user, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{
+				IsPR: isPR, Number: 11, Body: body,
+				User: plugins.GitUser{Name: user}, IssueAuthor: plugins.GitUser{Name: "author"},
+			},
+			Action: plugins.GitCommentActionCreated,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		addedLabels, removedLabel, comments = nil, plugins.Label{}, nil
+	})
+
+	It("Should hold by the author and owners", func() {
+		plugin := plugins.GetGitCommentPlugin("hold", clientSets)
+		for _, user := range []string{"author", "Reviewer", "approver"} {
+			addedLabels = nil
+			comment(plugin, true, user, "/hold")
+			Expect(addedLabels).Should(Equal([]plugins.Label{{Name: "do-not-merge/hold"}}))
+		}
+		addedLabels = nil
+		comment(plugin, false, "root", "/hold")
+		Expect(addedLabels).Should(Equal([]plugins.Label{{Name: "do-not-merge/hold"}}))
+	})
+	It("Should not hold by others", func() {
+		plugin := plugins.GetGitCommentPlugin("hold", clientSets)
+		comment(plugin, true, "root", "/hold")
+		Expect(addedLabels).Should(BeEmpty())
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("adding HOLD is restricted"))
+	})
+	It("Should unhold by allowed roles", func() {
+		plugin := plugins.GetGitCommentPlugin("hold", clientSets, "--unhold-roles=approvers")
+		comment(plugin, true, "approver", "/hold cancel")
+		Expect(removedLabel).Should(Equal(plugins.Label{Name: "do-not-merge/hold"}))
+
+		removedLabel = plugins.Label{}
+		comment(plugin, true, "author", "/unhold")
+		Expect(removedLabel).Should(Equal(plugins.Label{}))
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("removing the HOLD is restricted to approvers"))
+	})
+})
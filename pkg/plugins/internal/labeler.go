@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("labeler", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &labelerPlugin{
+			issueClient:  cs.GitIssueClient,
+			prClient:     cs.GitPRClient,
+			configClient: cs.PluginConfigClient,
+		}
+	})
+}
+
+type labelerPlugin struct {
+	issueClient  plugins.GitIssueClient
+	prClient     plugins.GitPRClient
+	configClient plugins.PluginConfigClient
+}
+
+func (lp *labelerPlugin) Name() string {
+	return "labeler"
+}
+
+func (lp *labelerPlugin) Description() string {
+	return "Adds and removes labels of pull requests by the paths of changed files."
+}
+
+func (lp *labelerPlugin) Usage() string {
+	return "Add 'labeler' plugin in configuration located under [.github/kuilei.yml](/.github/kuilei.yml), labels are configured in `labeler`"
+}
+
+func (lp *labelerPlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (lp *labelerPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR || (e.Action != "opened" && e.Action != "synchronize") {
+		return nil
+	}
+	cfg, err := lp.configClient.GetConfig(e.Repo.Owner.Name, e.Repo.Name)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Labeler) == 0 {
+		return nil
+	}
+	pr, err := lp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	files, err := lp.prClient.ListFiles(ctx, e.Repo, pr)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	// A label may be configured by several rules, it is kept if any of them matches
+	wants := map[string]bool{}
+	for _, rule := range cfg.Labeler {
+		wants[rule.Label] = wants[rule.Label] || matchLabelerRule(rule, paths)
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	var toAdd []plugins.Label
+	for _, rule := range cfg.Labeler {
+		want, ok := wants[rule.Label]
+		if !ok {
+			continue
+		}
+		delete(wants, rule.Label)
+		switch has := hasLabel(pr.Labels, rule.Label); {
+		case want && !has:
+			toAdd = append(toAdd, plugins.Label{Name: rule.Label})
+		case !want && has:
+			if err := lp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: rule.Label}); err != nil {
+				return err
+			}
+		}
+	}
+	if len(toAdd) == 0 {
+		return nil
+	}
+	return lp.issueClient.AddLabel(ctx, e.Repo, issue, toAdd)
+}
+
+// matchLabelerRule returns true if the changed files match every non-empty field of rule.
+func matchLabelerRule(rule plugins.LabelerRule, paths []string) bool {
+	if len(paths) == 0 || (len(rule.Any) == 0 && len(rule.All) == 0) {
+		return false
+	}
+	if len(rule.Any) > 0 {
+		matched := false
+		for _, path := range paths {
+			if matchLabelerGlobs(rule.Any, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, path := range paths {
+		if len(rule.All) > 0 && !matchLabelerGlobs(rule.All, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLabelerGlobs returns true if path matches any of the globs and none of the negated ones.
+func matchLabelerGlobs(globs []string, path string) bool {
+	matched, hasPositive := false, false
+	for _, glob := range globs {
+		if negated := strings.TrimPrefix(glob, "!"); negated != glob {
+			if pluginhelpers.MatchGlob(negated, path) {
+				return false
+			}
+			continue
+		}
+		hasPositive = true
+		matched = matched || pluginhelpers.MatchGlob(glob, path)
+	}
+	return matched || !hasPositive
+}
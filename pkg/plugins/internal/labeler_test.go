@@ -0,0 +1,99 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin labeler", func() {
+	var (
+		prLabels []plugins.Label
+		paths    []string
+		added    []plugins.Label
+		removed  []plugins.Label
+	)
+	rules := []plugins.LabelerRule{
+		{Label: "area/docs", Any: []string{"docs/**", "!docs/internal/**"}},
+		{Label: "area/plugins", Any: []string{"pkg/plugins/**"}},
+		{Label: "docs-only", All: []string{"**/*.md"}},
+		{Label: "no-tests", All: []string{"!**/*_test.go"}},
+		{Label: "area/api", Any: []string{"api/**"}},
+		{Label: "area/api", Any: []string{"**/*.proto"}},
+	}
+	plugin := plugins.GetGitCommentPlugin("labeler", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				added = append(added, labels...)
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{Number: number, Labels: prLabels}, nil
+			},
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				var files []plugins.GitCommitFile
+				for _, path := range paths {
+					files = append(files, plugins.GitCommitFile{Path: path})
+				}
+				return files, nil
+			},
+		}),
+		PluginConfigClient: mock.FakeConfigClient(func(owner, repo string) (plugins.Configuration, error) {
+			return plugins.Configuration{Labeler: rules}, nil
+		}),
+	})
+	do := func(action plugins.GitCommentEventAction) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 1},
+			Action:     action,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		prLabels, paths, added, removed = nil, nil, nil, nil
+	})
+
+	It("Should add labels of matched rules", func() {
+		paths = []string{"docs/README.md", "pkg/plugins/foo.go"}
+		do("opened")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "area/docs"}, {Name: "area/plugins"}, {Name: "no-tests"}}))
+		Expect(removed).Should(BeEmpty())
+	})
+	It("Should support negated globs", func() {
+		paths = []string{"docs/internal/notes.md", "pkg/plugins/foo_test.go"}
+		do("opened")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "area/plugins"}}))
+	})
+	It("Should require all files to match", func() {
+		paths = []string{"docs/a.md", "README.md"}
+		do("opened")
+		Expect(added).Should(ContainElement(plugins.Label{Name: "docs-only"}))
+	})
+	It("Should keep a label matched by any of its rules", func() {
+		prLabels = []plugins.Label{{Name: "area/api"}}
+		paths = []string{"proto/foo.proto"}
+		do("synchronize")
+		Expect(removed).Should(BeEmpty())
+	})
+	It("Should remove labels of rules which no longer match", func() {
+		prLabels = []plugins.Label{{Name: "area/docs"}, {Name: "docs-only"}, {Name: "lgtm"}, {Name: "no-tests"}}
+		paths = []string{"pkg/plugins/foo.go", "pkg/plugins/foo_test.go"}
+		do("synchronize")
+		Expect(removed).Should(Equal([]plugins.Label{{Name: "area/docs"}, {Name: "docs-only"}, {Name: "no-tests"}}))
+		Expect(added).Should(Equal([]plugins.Label{{Name: "area/plugins"}}))
+	})
+	It("Should ignore other events", func() {
+		paths = []string{"docs/README.md"}
+		do(plugins.GitCommentActionCreated)
+		Expect(added).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	lifecycleStaleLabel  = "lifecycle/stale"
+	lifecycleRottenLabel = "lifecycle/rotten"
+	lifecycleFrozenLabel = "lifecycle/frozen"
+)
+
+var lifecycleRegex = regexp.MustCompile(`(?mi)^/(remove-)?lifecycle[ \t]+(frozen|stale|rotten)[ \t]*$`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("lifecycle", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return newLifecyclePlugin(cs)
+	})
+	plugins.RegisterPeriodicPlugin("lifecycle", func(cs plugins.ClientSets) plugins.PeriodicPlugin {
+		return newLifecyclePlugin(cs)
+	})
+}
+
+func newLifecyclePlugin(cs plugins.ClientSets) *lifecyclePlugin {
+	return &lifecyclePlugin{
+		issueClient:  cs.GitIssueClient,
+		searchClient: cs.GitSearchClient,
+		configClient: cs.PluginConfigClient,
+		loggerClient: cs.LoggerClient,
+		botClient:    cs.BotClient,
+	}
+}
+
+// lifecyclePlugin handles lifecycle commands on events, and marks inactive issues and
+// pull requests stale, rotten and then closes them on its schedule.
+type lifecyclePlugin struct {
+	issueClient  plugins.GitIssueClient
+	searchClient plugins.GitSearchClient
+	configClient plugins.PluginConfigClient
+	loggerClient plugins.LoggerClient
+	botClient    plugins.BotClient
+}
+
+func (lp *lifecyclePlugin) Name() string {
+	return "lifecycle"
+}
+
+func (lp *lifecyclePlugin) Description() string {
+	return "Marks inactive issues and pull requests `lifecycle/stale`, then `lifecycle/rotten`, and closes them. `lifecycle/frozen` ones are never marked. " +
+		"Comments and edits of users who are not bots mark them as fresh again."
+}
+
+func (lp *lifecyclePlugin) Usage() string {
+	return "/[remove-]lifecycle frozen|stale|rotten"
+}
+
+func (lp *lifecyclePlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (lp *lifecyclePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if e.Action != plugins.GitCommentActionCreated && e.Action != plugins.GitCommentActionEdited {
+		return nil
+	}
+	matches := lifecycleRegex.FindAllStringSubmatch(plugins.CleanMarkdownComments(e.Body), -1)
+	if len(matches) == 0 {
+		return lp.revive(ctx, e)
+	}
+	if e.Action != plugins.GitCommentActionCreated {
+		return nil
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	for _, match := range matches {
+		label := plugins.Label{Name: "lifecycle/" + strings.ToLower(match[2])}
+		if match[1] != "" {
+			if err := lp.issueClient.RemoveLabel(ctx, e.Repo, issue, label); err != nil {
+				return err
+			}
+			continue
+		}
+		// An issue has one lifecycle label at most
+		for _, name := range []string{lifecycleStaleLabel, lifecycleRottenLabel, lifecycleFrozenLabel} {
+			if name == label.Name {
+				continue
+			}
+			if err := lp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: name}); err != nil {
+				return err
+			}
+		}
+		if err := lp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{label}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revive removes the stale and rotten labels of an issue on the activity of a user who is not a bot.
+func (lp *lifecyclePlugin) revive(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !hasLabel(e.Labels, lifecycleStaleLabel) && !hasLabel(e.Labels, lifecycleRottenLabel) {
+		return nil
+	}
+	bot, err := isBot(ctx, lp.botClient, e.User)
+	if err != nil || bot {
+		return err
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	for _, name := range []string{lifecycleStaleLabel, lifecycleRottenLabel} {
+		if !hasLabel(e.Labels, name) {
+			continue
+		}
+		if err := lp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lifecycleStep is one step of the lifecycle, which is applied to open issues matching query
+// and not updated for days.
+type lifecycleStep struct {
+	query   string
+	days    int
+	add     string
+	remove  string
+	close   bool
+	comment string
+}
+
+// Run closes rotten issues, marks stale issues rotten and marks inactive issues stale.
+// The later steps go first, so that an issue moves one step at most in one run.
+func (lp *lifecyclePlugin) Run(ctx context.Context, repo plugins.GitRepo) error {
+	cfg, err := lp.configClient.GetConfig(repo.Owner.Name, repo.Name)
+	if err != nil {
+		return err
+	}
+	staleDays, rottenDays, closeDays := cfg.Lifecycle.StaleDays, cfg.Lifecycle.RottenDays, cfg.Lifecycle.CloseDays
+	if staleDays <= 0 {
+		staleDays = 90
+	}
+	if rottenDays <= 0 {
+		rottenDays = 30
+	}
+	if closeDays <= 0 {
+		closeDays = 30
+	}
+	steps := []lifecycleStep{{
+		query: fmt.Sprintf("label:%s -label:%s", lifecycleRottenLabel, lifecycleFrozenLabel),
+		days:  closeDays, close: true,
+		comment: fmt.Sprintf("Rotten issues close after %dd of inactivity.\n"+
+			"Reopen the issue with `/reopen`, and mark it as fresh with `/remove-lifecycle rotten`.", closeDays),
+	}, {
+		query: fmt.Sprintf("label:%s -label:%s -label:%s", lifecycleStaleLabel, lifecycleRottenLabel, lifecycleFrozenLabel),
+		days:  rottenDays, add: lifecycleRottenLabel, remove: lifecycleStaleLabel,
+		comment: fmt.Sprintf("Stale issues rot after %dd of inactivity.\n"+
+			"Mark the issue as fresh with `/remove-lifecycle rotten`, or prevent it with `/lifecycle frozen`.\n"+
+			"Rotten issues close after an additional %dd of inactivity.", rottenDays, closeDays),
+	}, {
+		query: fmt.Sprintf("-label:%s -label:%s -label:%s", lifecycleStaleLabel, lifecycleRottenLabel, lifecycleFrozenLabel),
+		days:  staleDays, add: lifecycleStaleLabel,
+		comment: fmt.Sprintf("Issues go stale after %dd of inactivity.\n"+
+			"Mark the issue as fresh with `/remove-lifecycle stale`, or prevent it with `/lifecycle frozen`.\n"+
+			"Stale issues rot after an additional %dd of inactivity and eventually close.", staleDays, rottenDays),
+	}}
+	log := lp.loggerClient.GetLogger().WithValues("repo", repo.Name, "owner", repo.Owner.Name)
+	now := time.Now()
+	for _, step := range steps {
+		before := now.AddDate(0, 0, -step.days).UTC().Format(time.RFC3339)
+		issues, err := lp.searchClient.SearchIssues(ctx, repo, fmt.Sprintf("is:open %s updated:<%s", step.query, before))
+		if err != nil {
+			return fmt.Errorf("failed to search issues, %w", err)
+		}
+		for _, issue := range issues {
+			log.Info("Sync lifecycle", "number", issue.Number, "add", step.add, "close", step.close)
+			if err := lp.applyStep(ctx, repo, issue, step); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (lp *lifecyclePlugin) applyStep(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, step lifecycleStep) error {
+	if err := lp.issueClient.CreateIssueComment(ctx, repo, issue, plugins.GitIssueComment{
+		Body: step.comment,
+	}); err != nil {
+		return err
+	}
+	if step.close {
+		return lp.issueClient.CloseIssue(ctx, repo, issue, plugins.GitIssueCloseReasonNotPlanned)
+	}
+	if step.remove != "" {
+		if err := lp.issueClient.RemoveLabel(ctx, repo, issue, plugins.Label{Name: step.remove}); err != nil {
+			return err
+		}
+	}
+	return lp.issueClient.AddLabel(ctx, repo, issue, []plugins.Label{{Name: step.add}})
+}
@@ -0,0 +1,157 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin lifecycle", func() {
+	lock := sync.Mutex{}
+	var (
+		queries  []string
+		added    map[int][]string
+		removed  map[int][]string
+		comments map[int][]string
+		closed   []int
+	)
+	reset := func() {
+		lock.Lock()
+		defer lock.Unlock()
+		queries, closed = nil, nil
+		added, removed, comments = map[int][]string{}, map[int][]string{}, map[int][]string{}
+	}
+	reset()
+	clientSets := plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				lock.Lock()
+				defer lock.Unlock()
+				for _, l := range labels {
+					added[issue.Number] = append(added[issue.Number], l.Name)
+				}
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				lock.Lock()
+				defer lock.Unlock()
+				removed[issue.Number] = append(removed[issue.Number], label.Name)
+				return nil
+			},
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				lock.Lock()
+				defer lock.Unlock()
+				comments[issue.Number] = append(comments[issue.Number], comment.Body)
+				return nil
+			},
+			"CloseIssue": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, reason plugins.GitIssueCloseReason) error {
+				lock.Lock()
+				defer lock.Unlock()
+				Expect(reason).Should(Equal(plugins.GitIssueCloseReasonNotPlanned))
+				closed = append(closed, issue.Number)
+				return nil
+			},
+		}),
+		GitSearchClient: mock.FakeSearchClient(map[string]interface{}{
+			"SearchIssues": func(ctx context.Context, repo plugins.GitRepo, query string) ([]plugins.GitIssue, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				queries = append(queries, query)
+				switch {
+				case strings.HasPrefix(query, "is:open label:lifecycle/rotten"):
+					return []plugins.GitIssue{{Number: 1}}, nil
+				case strings.HasPrefix(query, "is:open label:lifecycle/stale"):
+					return []plugins.GitIssue{{Number: 2, IsPR: true}}, nil
+				}
+				return []plugins.GitIssue{{Number: 3}}, nil
+			},
+		}),
+		PluginConfigClient: mock.FakeConfigClient(func(owner, repo string) (plugins.Configuration, error) {
+			return plugins.Configuration{Lifecycle: plugins.LifecycleConfiguration{StaleDays: 60}}, nil
+		}),
+		LoggerClient: mock.FakeLoggerClient(),
+		BotClient:    mock.FakeBotClient("kuilei[bot]"),
+	}
+	plugin := plugins.GetGitCommentPlugin("lifecycle", clientSets)
+	comment := func(body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{Number: 10, Body: body},
+			Action:     plugins.GitCommentActionCreated,
+			Repo:       plugins.GitRepo{Name: "lifecycle-repo"},
+		})).Should(Succeed())
+	}
+
+	It("Should handle lifecycle commands", func() {
+		comment("/lifecycle frozen")
+		lock.Lock()
+		Expect(added[10]).Should(Equal([]string{"lifecycle/frozen"}))
+		Expect(removed[10]).Should(Equal([]string{"lifecycle/stale", "lifecycle/rotten"}))
+		lock.Unlock()
+
+		reset()
+		comment("/remove-lifecycle stale")
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(added[10]).Should(BeEmpty())
+		Expect(removed[10]).Should(Equal([]string{"lifecycle/stale"}))
+	})
+
+	It("Should mark issues as fresh on activity of users", func() {
+		reset()
+		event := func(user string, action plugins.GitCommentEventAction, labels ...string) {
+			e := plugins.GitCommentEvent{
+				GitComment: plugins.GitComment{Number: 11, Body: "Still valid", User: plugins.GitUser{Name: user}},
+				Action:     action,
+				Repo:       plugins.GitRepo{Name: "lifecycle-repo"},
+			}
+			for _, l := range labels {
+				e.Labels = append(e.Labels, plugins.Label{Name: l})
+			}
+			Expect(plugin.Do(context.TODO(), e)).Should(Succeed())
+		}
+		event("kuilei[bot]", plugins.GitCommentActionCreated, "lifecycle/stale")
+		event("dependabot[bot]", plugins.GitCommentActionEdited, "lifecycle/rotten")
+		event("someone", plugins.GitCommentActionCreated, "lifecycle/frozen")
+		event("someone", "labeled", "lifecycle/stale")
+		lock.Lock()
+		Expect(removed[11]).Should(BeEmpty())
+		lock.Unlock()
+
+		event("someone", plugins.GitCommentActionCreated, "lifecycle/stale")
+		event("someone", plugins.GitCommentActionEdited, "lifecycle/rotten")
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(removed[11]).Should(Equal([]string{"lifecycle/stale", "lifecycle/rotten"}))
+		Expect(added[11]).Should(BeEmpty())
+	})
+
+	It("Should close rotten, rot stale and mark inactive issues stale", func() {
+		reset()
+		periodic := plugins.GetPeriodicPlugin("lifecycle", clientSets)
+		Expect(periodic.Run(context.TODO(), plugins.GitRepo{Name: "lifecycle-repo"})).Should(Succeed())
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(queries).Should(HaveLen(3))
+		Expect(queries).Should(ContainElement(HavePrefix("is:open label:lifecycle/rotten -label:lifecycle/frozen updated:<")))
+		Expect(queries).Should(ContainElement(HavePrefix("is:open label:lifecycle/stale -label:lifecycle/rotten -label:lifecycle/frozen updated:<")))
+		Expect(queries).Should(ContainElement(And(
+			HavePrefix("is:open -label:lifecycle/stale -label:lifecycle/rotten -label:lifecycle/frozen updated:<"),
+			ContainSubstring(time.Now().AddDate(0, 0, -60).UTC().Format("2006-01-02")),
+		)))
+
+		Expect(closed).Should(Equal([]int{1}))
+		Expect(comments[1][0]).Should(ContainSubstring("Rotten issues close after 30d of inactivity"))
+		Expect(removed[2][0]).Should(Equal("lifecycle/stale"))
+		Expect(added[2][0]).Should(Equal("lifecycle/rotten"))
+		Expect(comments[2][0]).Should(ContainSubstring("Stale issues rot after 30d"))
+		Expect(added[3][0]).Should(Equal("lifecycle/stale"))
+		Expect(comments[3][0]).Should(ContainSubstring("Issues go stale after 60d"))
+	})
+})
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var milestoneRegex = regexp.MustCompile(`(?mi)^/milestone[ \t]+(.+?)[ \t]*$`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("milestone", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &milestonePlugin{
+			issueClient: cs.GitIssueClient,
+			orgClient:   cs.GitOrgClient,
+		}
+		return plugin
+	})
+}
+
+type milestonePlugin struct {
+	issueClient plugins.GitIssueClient
+	orgClient   plugins.GitOrgClient
+
+	maintainersTeam string
+	maintainers     []string
+}
+
+func (mp *milestonePlugin) Name() string {
+	return "milestone"
+}
+
+func (mp *milestonePlugin) Description() string {
+	return "Sets or clears the milestone of an issue or pull request."
+}
+
+func (mp *milestonePlugin) Usage() string {
+	return "/milestone <title>|clear"
+}
+
+func (mp *milestonePlugin) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&mp.maintainersTeam, "maintainers-team", "milestone-maintainers",
+		"Team whose members can set milestones, either team-slug in the org of the repo or org/team-slug, "+
+			"a team which does not exist has no members")
+	flags.StringSliceVar(&mp.maintainers, "maintainers", nil, "Users who can set milestones besides the team members")
+}
+
+func (mp *milestonePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if e.Action != plugins.GitCommentActionCreated {
+		return nil
+	}
+	match := milestoneRegex.FindStringSubmatch(plugins.CleanMarkdownComments(e.Body))
+	if match == nil {
+		return nil
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+
+	allowed, err := mp.isMaintainer(ctx, e)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		resp := fmt.Sprintf("setting milestones is restricted to members of the %s team.", mp.teamOf(e.Repo))
+		return mp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+
+	title := match[1]
+	if strings.EqualFold(title, "clear") {
+		return mp.issueClient.SetMilestone(ctx, e.Repo, issue, 0)
+	}
+	milestones, err := mp.issueClient.ListMilestones(ctx, e.Repo)
+	if err != nil {
+		return err
+	}
+	for _, m := range milestones {
+		if strings.EqualFold(m.Title, title) {
+			return mp.issueClient.SetMilestone(ctx, e.Repo, issue, m.Number)
+		}
+	}
+	resp := fmt.Sprintf("the milestone `%s` does not exist, the open milestones are:\n", title)
+	for _, m := range milestones {
+		resp += fmt.Sprintf("- `%s`\n", m.Title)
+	}
+	resp += "\nUse `/milestone clear` to clear the milestone."
+	return mp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+		Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+	})
+}
+
+func (mp *milestonePlugin) isMaintainer(ctx context.Context, e plugins.GitCommentEvent) (bool, error) {
+	for _, name := range mp.maintainers {
+		if strings.EqualFold(name, e.User.Name) {
+			return true, nil
+		}
+	}
+	if mp.maintainersTeam == "" {
+		return false, nil
+	}
+	org, team, _ := strings.Cut(mp.teamOf(e.Repo), "/")
+	return mp.orgClient.IsTeamMember(ctx, org, team, e.User.Name)
+}
+
+// teamOf returns the maintainers team as org/team-slug.
+func (mp *milestonePlugin) teamOf(repo plugins.GitRepo) string {
+	if strings.Contains(mp.maintainersTeam, "/") {
+		return mp.maintainersTeam
+	}
+	return repo.Owner.Name + "/" + mp.maintainersTeam
+}
@@ -0,0 +1,71 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin milestone", func() {
+	var (
+		milestones []int
+		comments   []plugins.GitIssueComment
+		teams      []string
+	)
+	plugin := plugins.GetGitCommentPlugin("milestone", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+			"ListMilestones": func(ctx context.Context, repo plugins.GitRepo) ([]plugins.GitMilestone, error) {
+				return []plugins.GitMilestone{{Number: 1, Title: "v1.1"}, {Number: 2, Title: "v1.2"}}, nil
+			},
+			"SetMilestone": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, milestone int) error {
+				milestones = append(milestones, milestone)
+				return nil
+			},
+		}),
+		GitOrgClient: mock.FakeOrgClient(map[string]interface{}{
+			"IsTeamMember": func(ctx context.Context, org, team, login string) (bool, error) {
+				teams = append(teams, org+"/"+team)
+				return login == "member", nil
+			},
+		}),
+	}, "--maintainers=lead")
+	comment := func(user, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{Number: 11, Body: body, User: plugins.GitUser{Name: user}},
+			Action:     plugins.GitCommentActionCreated,
+			Repo:       plugins.GitRepo{Name: "repo", Owner: plugins.GitUser{Name: "org"}},
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		milestones, comments, teams = nil, nil, nil
+	})
+
+	It("Should set milestones by title", func() {
+		comment("member", "/milestone v1.2")
+		comment("Lead", "/milestone V1.1")
+		comment("member", "/milestone clear")
+		Expect(milestones).Should(Equal([]int{2, 1, 0}))
+		Expect(teams).Should(ContainElement("org/milestone-maintainers"))
+	})
+	It("Should list open milestones if the milestone does not exist", func() {
+		comment("member", "/milestone v2.0")
+		Expect(milestones).Should(BeEmpty())
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("the milestone `v2.0` does not exist"))
+		Expect(comments[0].Body).Should(ContainSubstring("- `v1.1`\n- `v1.2`\n"))
+	})
+	It("Should refuse others", func() {
+		comment("stranger", "/milestone v1.2")
+		Expect(milestones).Should(BeEmpty())
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("restricted to members of the org/milestone-maintainers team"))
+	})
+})
@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+// NeedsRebaseUnknownDelay is the delay to re-check PRs whose mergeability is still being calculated.
+var NeedsRebaseUnknownDelay = 10 * time.Second
+
+const needsRebaseLabel = "needs-rebase"
+
+func init() {
+	plugins.RegisterGitCommentPlugin("needs-rebase", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return newNeedsRebasePlugin(cs)
+	})
+	plugins.RegisterPeriodicPlugin("needs-rebase", func(cs plugins.ClientSets) plugins.PeriodicPlugin {
+		return newNeedsRebasePlugin(cs)
+	})
+}
+
+func newNeedsRebasePlugin(cs plugins.ClientSets) *needsRebasePlugin {
+	return &needsRebasePlugin{
+		issueClient:  cs.GitIssueClient,
+		prClient:     cs.GitPRClient,
+		searchClient: cs.GitSearchClient,
+		loggerClient: cs.LoggerClient,
+	}
+}
+
+// needsRebasePlugin labels PRs with merge conflicts. A PR is checked on its events, and all open
+// PRs of the repo are checked on its schedule for the conflicts caused by pushes to the base branches.
+type needsRebasePlugin struct {
+	issueClient  plugins.GitIssueClient
+	prClient     plugins.GitPRClient
+	searchClient plugins.GitSearchClient
+	loggerClient plugins.LoggerClient
+}
+
+func (p *needsRebasePlugin) Name() string {
+	return "needs-rebase"
+}
+
+func (p *needsRebasePlugin) Description() string {
+	return fmt.Sprintf("Adds `%s` label to pull requests with merge conflicts, and removes it once they can be merged again.", needsRebaseLabel)
+}
+
+func (p *needsRebasePlugin) Usage() string {
+	return "Add 'needs-rebase' plugin in configuration located under [.github/kuilei.yml](/.github/kuilei.yml)"
+}
+
+func (p *needsRebasePlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (p *needsRebasePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	log := p.logger(e.Repo).WithValues("pr", e.Number)
+	unknown, err := p.syncNumber(ctx, e.Repo, e.Number, log)
+	if err != nil || !unknown {
+		return err
+	}
+	// Getting the PR starts the calculation of its mergeability, check it again in background
+	// rather than blocking the other plugins of the event
+	log.Info("Mergeability is unknown, check again after", "after", NeedsRebaseUnknownDelay)
+	time.AfterFunc(NeedsRebaseUnknownDelay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := p.syncNumber(ctx, e.Repo, e.Number, log); err != nil {
+			log.Error(err, "Failed to sync needs-rebase label")
+		}
+	})
+	return nil
+}
+
+// Run syncs the label of all open PRs of a repo, PRs whose mergeability is unknown are
+// checked again once after NeedsRebaseUnknownDelay.
+func (p *needsRebasePlugin) Run(ctx context.Context, repo plugins.GitRepo) error {
+	log := p.logger(repo)
+	unknown, err := p.syncOnce(ctx, repo, log)
+	if err != nil || !unknown {
+		return err
+	}
+	log.Info("Mergeability is unknown, check again after", "after", NeedsRebaseUnknownDelay)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(NeedsRebaseUnknownDelay):
+	}
+	_, err = p.syncOnce(ctx, repo, log)
+	return err
+}
+
+func (p *needsRebasePlugin) logger(repo plugins.GitRepo) logr.Logger {
+	return p.loggerClient.GetLogger().WithName("needs_rebase").WithValues("repo", repo.Name, "owner", repo.Owner.Name)
+}
+
+// syncOnce syncs the label of all open PRs of a repo, it returns true if the mergeability
+// of any PR is unknown.
+func (p *needsRebasePlugin) syncOnce(ctx context.Context, repo plugins.GitRepo, log logr.Logger) (unknown bool, err error) {
+	results, err := p.searchClient.SearchPR(ctx, repo, plugins.PullRequestStateOpen)
+	if err != nil {
+		return false, fmt.Errorf("failed to search prs, %w", err)
+	}
+	for _, result := range results {
+		prUnknown, err := p.syncPR(ctx, repo, result.GitPullRequest, log)
+		if err != nil {
+			return false, err
+		}
+		unknown = unknown || prUnknown
+	}
+	return unknown, nil
+}
+
+// syncNumber syncs the label of a PR by its number, it returns true if its mergeability is unknown.
+func (p *needsRebasePlugin) syncNumber(ctx context.Context, repo plugins.GitRepo, number int, log logr.Logger) (bool, error) {
+	pr, err := p.prClient.GetPR(ctx, repo, number)
+	if err != nil {
+		return false, err
+	}
+	if pr.State != plugins.PullRequestStateOpen {
+		return false, nil
+	}
+	return p.syncPR(ctx, repo, pr, log)
+}
+
+// syncPR adds or removes the label of a PR by its mergeability, it returns true if the
+// mergeability is unknown.
+func (p *needsRebasePlugin) syncPR(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest, log logr.Logger) (bool, error) {
+	issue := plugins.GitIssue{Number: pr.Number}
+	labeled := hasLabel(pr.Labels, needsRebaseLabel)
+	switch pr.Mergeable {
+	case plugins.GitMergeableStateConflicting:
+		if labeled {
+			return false, nil
+		}
+		log.Info("Add needs-rebase label", "pr", pr.Number)
+		if err := p.issueClient.AddLabel(ctx, repo, issue, []plugins.Label{{Name: needsRebaseLabel}}); err != nil {
+			return false, err
+		}
+		return false, p.issueClient.CreateIssueComment(ctx, repo, issue, plugins.GitIssueComment{
+			Body: fmt.Sprintf("@%s: This PR has merge conflicts with its base branch, please rebase it.", pr.User.Name),
+		})
+	case plugins.GitMergeableStateMergeable:
+		if !labeled {
+			return false, nil
+		}
+		log.Info("Remove needs-rebase label", "pr", pr.Number)
+		return false, p.issueClient.RemoveLabel(ctx, repo, issue, plugins.Label{Name: needsRebaseLabel})
+	}
+	return true, nil
+}
@@ -0,0 +1,149 @@
+package internal_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/internal"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin needs-rebase", func() {
+	internal.NeedsRebaseUnknownDelay = 100 * time.Millisecond
+
+	lock := sync.Mutex{}
+	var (
+		prs      map[int]*plugins.GitPullRequest
+		comments map[int]int
+		searches int
+	)
+	labeled := func(number int) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, l := range prs[number].Labels {
+			if l.Name == "needs-rebase" {
+				return true
+			}
+		}
+		return false
+	}
+	BeforeEach(func() {
+		lock.Lock()
+		defer lock.Unlock()
+		prs = map[int]*plugins.GitPullRequest{
+			1: {Number: 1, Mergeable: plugins.GitMergeableStateConflicting, User: plugins.GitUser{Name: "foo"}},
+			2: {Number: 2, Mergeable: plugins.GitMergeableStateMergeable, Labels: []plugins.Label{{Name: "needs-rebase"}}},
+			3: {Number: 3, Mergeable: plugins.GitMergeableStateUnknown},
+		}
+		for _, pr := range prs {
+			pr.State = plugins.PullRequestStateOpen
+		}
+		comments, searches = map[int]int{}, 0
+	})
+
+	clientSets := plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				lock.Lock()
+				defer lock.Unlock()
+				prs[issue.Number].Labels = append(prs[issue.Number].Labels, labels...)
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				lock.Lock()
+				defer lock.Unlock()
+				prs[issue.Number].Labels = nil
+				return nil
+			},
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				lock.Lock()
+				defer lock.Unlock()
+				comments[issue.Number]++
+				return nil
+			},
+		}),
+		GitSearchClient: mock.FakeSearchClient(map[string]interface{}{
+			"SearchPR": func(ctx context.Context, repo plugins.GitRepo, state string) ([]plugins.GitPullRequestSearchResult, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				Expect(repo.Name).Should(Equal("needs-rebase-repo"))
+				searches++
+				var results []plugins.GitPullRequestSearchResult
+				for _, number := range []int{1, 2, 3} {
+					pr := *prs[number]
+					pr.Labels = append([]plugins.Label{}, pr.Labels...)
+					results = append(results, plugins.GitPullRequestSearchResult{GitPullRequest: pr})
+				}
+				return results, nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				lock.Lock()
+				defer lock.Unlock()
+				pr := *prs[number]
+				pr.Labels = append([]plugins.Label{}, pr.Labels...)
+				return pr, nil
+			},
+		}),
+		LoggerClient: mock.FakeLoggerClient(),
+	}
+	plugin := plugins.GetGitCommentPlugin("needs-rebase", clientSets)
+
+	event := func(number int) plugins.GitCommentEvent {
+		return plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: number},
+			Action:     "synchronize",
+			Repo:       plugins.GitRepo{Name: "needs-rebase-repo"},
+		}
+	}
+
+	It("Should only sync the PR of the event", func() {
+		Expect(plugin.Do(context.TODO(), event(1))).Should(Succeed())
+		Expect(labeled(1)).Should(BeTrue())
+		Expect(labeled(2)).Should(BeTrue())
+
+		// Mergeability of PR 3 is checked again after a delay
+		Expect(plugin.Do(context.TODO(), event(3))).Should(Succeed())
+		Expect(labeled(3)).Should(BeFalse())
+		lock.Lock()
+		prs[3].Mergeable = plugins.GitMergeableStateConflicting
+		lock.Unlock()
+		// The comment is created after the label
+		Eventually(func() int {
+			lock.Lock()
+			defer lock.Unlock()
+			return comments[3]
+		}, time.Second, 10*time.Millisecond).Should(Equal(1))
+		Expect(labeled(3)).Should(BeTrue())
+
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(searches).Should(BeZero())
+		Expect(comments).Should(Equal(map[int]int{1: 1, 3: 1}))
+	})
+
+	It("Should sync all open PRs periodically", func() {
+		// Mergeability of PR 3 is checked again after a delay by the periodic run
+		go func() {
+			time.Sleep(internal.NeedsRebaseUnknownDelay / 2)
+			lock.Lock()
+			defer lock.Unlock()
+			prs[3].Mergeable = plugins.GitMergeableStateConflicting
+		}()
+		periodic := plugins.GetPeriodicPlugin("needs-rebase", clientSets)
+		Expect(periodic.Run(context.TODO(), plugins.GitRepo{Name: "needs-rebase-repo"})).Should(Succeed())
+		Expect(labeled(1)).Should(BeTrue())
+		Expect(labeled(2)).Should(BeFalse())
+		Expect(labeled(3)).Should(BeTrue())
+
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(comments).Should(Equal(map[int]int{1: 1, 3: 1}))
+	})
+})
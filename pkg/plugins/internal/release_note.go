@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	releaseNoteLabel       = "release-note"
+	releaseNoteNoneLabel   = "release-note-none"
+	releaseNoteNeededLabel = "do-not-merge/release-note-label-needed"
+)
+
+var (
+	releaseNoteLabels    = []string{releaseNoteLabel, releaseNoteNoneLabel, releaseNoteNeededLabel}
+	releaseNoteNoneRegex = regexp.MustCompile(`(?mi)^/release-note-none[ \t]*$`)
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("release-note", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &releaseNotePlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			ownerClient: cs.OwnersClient,
+		}
+	})
+}
+
+type releaseNotePlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	ownerClient plugins.OwnersClient
+}
+
+func (rp *releaseNotePlugin) Name() string {
+	return "release-note"
+}
+
+func (rp *releaseNotePlugin) Description() string {
+	return "Labels pull requests by the ```release-note``` block in their body, `" + releaseNoteNeededLabel + "` is added if there is none."
+}
+
+func (rp *releaseNotePlugin) Usage() string {
+	return "/release-note-none"
+}
+
+func (rp *releaseNotePlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (rp *releaseNotePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "opened", plugins.GitCommentActionReopened, plugins.GitCommentActionEdited:
+		pr, err := rp.prClient.GetPR(ctx, e.Repo, e.Number)
+		if err != nil {
+			return err
+		}
+		return rp.ensureLabel(ctx, e.Repo, pr, wantReleaseNoteLabel(pr))
+	case plugins.GitCommentActionCreated:
+		if !releaseNoteNoneRegex.MatchString(plugins.CleanMarkdownComments(e.Body)) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	_, approvers, err := ownersOf(ctx, rp.prClient, rp.ownerClient, e)
+	if err != nil {
+		return err
+	}
+	if !approvers.Has(strings.ToLower(e.User.Name)) {
+		resp := "only approvers in OWNERS files can use `/release-note-none`."
+		return rp.issueClient.CreateIssueComment(ctx, e.Repo, plugins.GitIssue{Number: e.Number}, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+	pr, err := rp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	return rp.ensureLabel(ctx, e.Repo, pr, releaseNoteNoneLabel)
+}
+
+// wantReleaseNoteLabel returns the release note label of pr by its body. A pull request
+// without release note keeps `release-note-none` set by `/release-note-none`.
+func wantReleaseNoteLabel(pr plugins.GitPullRequest) string {
+	note, ok := plugins.ExtractReleaseNote(pr.Body)
+	switch {
+	case ok && !plugins.IsReleaseNoteNone(note):
+		return releaseNoteLabel
+	case ok, hasLabel(pr.Labels, releaseNoteNoneLabel):
+		return releaseNoteNoneLabel
+	}
+	return releaseNoteNeededLabel
+}
+
+// ensureLabel adds want and removes the other release note labels.
+func (rp *releaseNotePlugin) ensureLabel(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest, want string) error {
+	issue := plugins.GitIssue{Number: pr.Number}
+	for _, label := range releaseNoteLabels {
+		if label != want && hasLabel(pr.Labels, label) {
+			if err := rp.issueClient.RemoveLabel(ctx, repo, issue, plugins.Label{Name: label}); err != nil {
+				return err
+			}
+		}
+	}
+	if hasLabel(pr.Labels, want) {
+		return nil
+	}
+	return rp.issueClient.AddLabel(ctx, repo, issue, []plugins.Label{{Name: want}})
+}
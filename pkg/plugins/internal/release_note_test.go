@@ -0,0 +1,95 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin release-note", func() {
+	var (
+		pr       plugins.GitPullRequest
+		added    []plugins.Label
+		removed  []plugins.Label
+		comments []plugins.GitIssueComment
+	)
+	plugin := plugins.GetGitCommentPlugin("release-note", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				added = append(added, labels...)
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label)
+				return nil
+			},
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return pr, nil
+			},
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return []plugins.GitCommitFile{{Path: "main.go"}}, nil
+			},
+		}),
+		OwnersClient: mock.FakeOwnerClient(func(owner, repo, file string) (plugins.OwnersConfiguration, error) {
+			return plugins.OwnersConfiguration{Approvers: []string{"Approver"}}, nil
+		}),
+	})
+	do := func(action plugins.GitCommentEventAction, user, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 1, User: plugins.GitUser{Name: user}, Body: body},
+			Action:     action,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		pr, added, removed, comments = plugins.GitPullRequest{Number: 1}, nil, nil, nil
+	})
+
+	It("Should require a release note", func() {
+		pr.Body = "Fix foo\n<!--\n```release-note\nFoo\n```\n-->"
+		do("opened", "author", "")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "do-not-merge/release-note-label-needed"}}))
+	})
+	It("Should label release notes", func() {
+		pr.Body = "Fix foo\n```release-note\nFixed foo.\n```\n"
+		pr.Labels = []plugins.Label{{Name: "do-not-merge/release-note-label-needed"}}
+		do(plugins.GitCommentActionEdited, "author", "")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "release-note"}}))
+		Expect(removed).Should(Equal([]plugins.Label{{Name: "do-not-merge/release-note-label-needed"}}))
+	})
+	It("Should label empty release notes as none", func() {
+		pr.Body = "```release-note\r\nNONE\r\n```"
+		pr.Labels = []plugins.Label{{Name: "release-note"}}
+		do(plugins.GitCommentActionReopened, "author", "")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "release-note-none"}}))
+		Expect(removed).Should(Equal([]plugins.Label{{Name: "release-note"}}))
+	})
+	It("Should keep release-note-none without release note", func() {
+		pr.Labels = []plugins.Label{{Name: "release-note-none"}}
+		do(plugins.GitCommentActionEdited, "author", "")
+		Expect(added).Should(BeEmpty())
+		Expect(removed).Should(BeEmpty())
+	})
+	It("Should allow approvers to use /release-note-none", func() {
+		pr.Labels = []plugins.Label{{Name: "do-not-merge/release-note-label-needed"}}
+		do(plugins.GitCommentActionCreated, "approver", "/release-note-none")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "release-note-none"}}))
+		Expect(removed).Should(Equal([]plugins.Label{{Name: "do-not-merge/release-note-label-needed"}}))
+		Expect(comments).Should(BeEmpty())
+	})
+	It("Should reject /release-note-none from others", func() {
+		do(plugins.GitCommentActionCreated, "author", "/release-note-none")
+		Expect(added).Should(BeEmpty())
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("only approvers"))
+	})
+})
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var (
+	retestRegex = regexp.MustCompile(`(?mi)^/retest[ \t]*$`)
+	testRegex   = regexp.MustCompile(`(?mi)^/test[ \t]+(.+?)[ \t]*$`)
+)
+
+// failedCheckConclusions are the conclusions of check runs which are retested by /retest.
+var failedCheckConclusions = sets.NewString(
+	plugins.GitCheckConclusionStateFailure,
+	plugins.GitCheckConclusionStateTimedOut,
+	plugins.GitCheckConclusionStateCancelled,
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("retest", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		plugin := &retestPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			repoClient:  cs.GitRepoClient,
+			orgClient:   cs.GitOrgClient,
+		}
+		return plugin
+	})
+}
+
+type retestPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	repoClient  plugins.GitRepoClient
+	orgClient   plugins.GitOrgClient
+}
+
+func (rp *retestPlugin) Name() string {
+	return "retest"
+}
+
+func (rp *retestPlugin) Description() string {
+	return "Re-requests failed or named GitHub check runs of a pull request."
+}
+
+func (rp *retestPlugin) Usage() string {
+	return "/retest, /test <name>|all"
+}
+
+func (rp *retestPlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (rp *retestPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR || e.Action != plugins.GitCommentActionCreated {
+		return nil
+	}
+	bodyClean := plugins.CleanMarkdownComments(e.Body)
+	retestMatch := retestRegex.MatchString(bodyClean)
+	var args []string
+	for _, match := range testRegex.FindAllStringSubmatch(bodyClean, -1) {
+		args = append(args, match[1])
+	}
+	if !retestMatch && len(args) == 0 {
+		return nil
+	}
+
+	pr, err := rp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	// Untrusted users can only test their own pull requests, after a trusted user commented /ok-to-test
+	trusted, err := plugins.IsTrusted(ctx, rp.orgClient, rp.repoClient, e.Repo, e.User.Name, e.AuthorAssociation)
+	if err != nil {
+		return err
+	}
+	okToTest := hasLabel(pr.Labels, okToTestLabel) && !hasLabel(pr.Labels, needsOkToTestLabel)
+	if !trusted && (!strings.EqualFold(pr.User.Name, e.User.Name) || !okToTest) {
+		resp := "running tests is restricted to members and collaborators, or the author of a pull request which is `ok-to-test`."
+		return rp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+
+	checks, err := rp.repoClient.ListChecks(ctx, e.Repo, pr.Head.SHA)
+	if err != nil {
+		return err
+	}
+	runs := sets.NewInt64()
+	suites := sets.NewInt64()
+	var unknown []string
+	for _, name := range testNames(args, checks) {
+		found := false
+		for _, check := range checks {
+			switch {
+			case strings.EqualFold(name, "all"):
+				suites.Insert(check.SuiteID)
+				found = true
+			case strings.EqualFold(name, check.Name):
+				runs.Insert(check.ID)
+				found = true
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+	if retestMatch {
+		for _, check := range checks {
+			if check.Status == plugins.GitCheckStatusCompleted && failedCheckConclusions.Has(check.Conclusion) {
+				runs.Insert(check.ID)
+			}
+		}
+	}
+
+	for _, id := range suites.List() {
+		if err := rp.repoClient.RerequestCheckSuite(ctx, e.Repo, id); err != nil {
+			return err
+		}
+	}
+	for _, id := range runs.List() {
+		if err := rp.repoClient.RerequestCheckRun(ctx, e.Repo, id); err != nil {
+			return err
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	available := sets.NewString()
+	for _, check := range checks {
+		available.Insert(check.Name)
+	}
+	resp := fmt.Sprintf("the following checks do not exist: `%s`.\n\nThe available checks are:\n", strings.Join(unknown, "`, `"))
+	for _, name := range available.List() {
+		resp += fmt.Sprintf("- `%s`\n", name)
+	}
+	return rp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+		Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+	})
+}
+
+// testNames returns the check names of the /test arguments. Each argument is a single name if it
+// matches a check, which may contain spaces like "build (ubuntu-latest)", otherwise it is split
+// into several names by whitespace.
+func testNames(args []string, checks []plugins.GitCommitCheck) []string {
+	var names []string
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		matched := strings.EqualFold(arg, "all")
+		for _, check := range checks {
+			matched = matched || strings.EqualFold(arg, check.Name)
+		}
+		if matched {
+			names = append(names, arg)
+			continue
+		}
+		names = append(names, strings.Fields(arg)...)
+	}
+	return names
+}
@@ -0,0 +1,122 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin retest", func() {
+	var (
+		runs, suites []int64
+		comments     []plugins.GitIssueComment
+		prLabels     []plugins.Label
+	)
+	plugin := plugins.GetGitCommentPlugin("retest", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{
+					Number: number, User: plugins.GitUser{Name: "author"}, Labels: prLabels,
+					Head: plugins.GitBranch{SHA: "head-sha"},
+				}, nil
+			},
+		}),
+		GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+			"ListChecks": func(ctx context.Context, repo plugins.GitRepo, ref string) ([]plugins.GitCommitCheck, error) {
+				Expect(ref).Should(Equal("head-sha"))
+				return []plugins.GitCommitCheck{
+					{ID: 1, SuiteID: 10, Name: "build", Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateSuccess},
+					{ID: 2, SuiteID: 10, Name: "unit", Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateFailure},
+					{ID: 3, SuiteID: 20, Name: "e2e", Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateTimedOut},
+					{ID: 4, SuiteID: 20, Name: "lint", Status: plugins.GitCheckStatusInProgress},
+					{ID: 5, SuiteID: 30, Name: "build (ubuntu-latest)", Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateSuccess},
+				}, nil
+			},
+			"RerequestCheckSuite": func(ctx context.Context, repo plugins.GitRepo, id int64) error {
+				suites = append(suites, id)
+				return nil
+			},
+			"RerequestCheckRun": func(ctx context.Context, repo plugins.GitRepo, id int64) error {
+				runs = append(runs, id)
+				return nil
+			},
+			"IsCollaborator": func(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+				return false, nil
+			},
+		}),
+		GitOrgClient: mock.FakeOrgClient(map[string]interface{}{
+			"IsOrgMember": func(ctx context.Context, org, login string) (bool, error) {
+				return login == "private-member", nil
+			},
+		}),
+	})
+	comment := func(user, association, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{
+				IsPR: true, Number: 11, Body: body,
+				User: plugins.GitUser{Name: user}, AuthorAssociation: association,
+			},
+			Action: plugins.GitCommentActionCreated,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		runs, suites, comments, prLabels = nil, nil, nil, nil
+	})
+
+	It("Should retest failed check runs", func() {
+		prLabels = []plugins.Label{{Name: "ok-to-test"}}
+		comment("author", "CONTRIBUTOR", "/retest")
+		Expect(runs).Should(Equal([]int64{2, 3}))
+		Expect(suites).Should(BeEmpty())
+	})
+	It("Should test named check runs", func() {
+		comment("member", "MEMBER", "/test build\n/test Lint")
+		Expect(runs).Should(Equal([]int64{1, 4}))
+	})
+	It("Should test check runs whose names contain spaces", func() {
+		comment("member", "MEMBER", "/test build (Ubuntu-latest)")
+		Expect(runs).Should(Equal([]int64{5}))
+		Expect(comments).Should(BeEmpty())
+	})
+	It("Should test all check suites", func() {
+		comment("member", "MEMBER", "/test all")
+		Expect(suites).Should(Equal([]int64{10, 20, 30}))
+		Expect(runs).Should(BeEmpty())
+	})
+	It("Should reply unknown check runs", func() {
+		comment("member", "MEMBER", "/test build foo")
+		Expect(runs).Should(Equal([]int64{1}))
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0].Body).Should(ContainSubstring("the following checks do not exist: `foo`"))
+		Expect(comments[0].Body).Should(ContainSubstring("- `build`\n- `build (ubuntu-latest)`\n- `e2e`\n- `lint`\n- `unit`\n"))
+	})
+	It("Should respect the ok-to-test gate", func() {
+		comment("stranger", "CONTRIBUTOR", "/retest")
+		prLabels = []plugins.Label{{Name: "needs-ok-to-test"}}
+		comment("author", "FIRST_TIME_CONTRIBUTOR", "/retest")
+		// The gate is closed until ok-to-test is labeled, even if needs-ok-to-test is missing
+		prLabels = nil
+		comment("author", "FIRST_TIME_CONTRIBUTOR", "/retest")
+		Expect(runs).Should(BeEmpty())
+		Expect(comments).Should(HaveLen(3))
+		Expect(comments[1].Body).Should(ContainSubstring("running tests is restricted"))
+		Expect(comments[2].Body).Should(ContainSubstring("running tests is restricted"))
+
+		comment("member", "MEMBER", "/retest")
+		Expect(runs).Should(Equal([]int64{2, 3}))
+
+		runs = nil
+		comment("private-member", "CONTRIBUTOR", "/retest")
+		Expect(runs).Should(Equal([]int64{2, 3}))
+	})
+})
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	sizeLabelPrefix   = "size/"
+	gitAttributesFile = ".gitattributes"
+)
+
+var sizeLabels = []string{"size/XS", "size/S", "size/M", "size/L", "size/XL", "size/XXL"}
+
+func init() {
+	plugins.RegisterGitCommentPlugin("size", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &sizePlugin{
+			issueClient:  cs.GitIssueClient,
+			prClient:     cs.GitPRClient,
+			repoClient:   cs.GitRepoClient,
+			configClient: cs.PluginConfigClient,
+		}
+	})
+}
+
+type sizePlugin struct {
+	issueClient  plugins.GitIssueClient
+	prClient     plugins.GitPRClient
+	repoClient   plugins.GitRepoClient
+	configClient plugins.PluginConfigClient
+}
+
+func (sp *sizePlugin) Name() string {
+	return "size"
+}
+
+func (sp *sizePlugin) Description() string {
+	return "Labels pull requests with `size/XS` ... `size/XXL` by the number of changed lines, generated files are not counted."
+}
+
+func (sp *sizePlugin) Usage() string {
+	return "Add 'size' plugin in configuration located under [.github/kuilei.yml](/.github/kuilei.yml), thresholds are configured in `size`"
+}
+
+func (sp *sizePlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (sp *sizePlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "opened", "synchronize", plugins.GitCommentActionReopened:
+	default:
+		return nil
+	}
+	cfg, err := sp.configClient.GetConfig(e.Repo.Owner.Name, e.Repo.Name)
+	if err != nil {
+		return err
+	}
+	pr, err := sp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	attributes, err := sp.repoClient.GetFile(ctx, e.Repo, pr.Base.SHA, gitAttributesFile)
+	if err != nil {
+		return err
+	}
+	generated := parseGeneratedRules(string(attributes))
+	files, err := sp.prClient.ListFiles(ctx, e.Repo, pr)
+	if err != nil {
+		return err
+	}
+	size := 0
+	for _, file := range files {
+		if generated.match(file.Path) || pluginhelpers.MatchAnyGlob(cfg.Size.GeneratedFiles, file.Path) {
+			continue
+		}
+		size += file.Additions + file.Deletions
+	}
+
+	want := sizeLabel(cfg.Size, size)
+	issue := plugins.GitIssue{Number: e.Number}
+	for _, label := range pr.Labels {
+		if strings.HasPrefix(label.Name, sizeLabelPrefix) && label.Name != want {
+			if err := sp.issueClient.RemoveLabel(ctx, e.Repo, issue, label); err != nil {
+				return err
+			}
+		}
+	}
+	if hasLabel(pr.Labels, want) {
+		return nil
+	}
+	return sp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: want}})
+}
+
+// sizeLabel returns the label of the first threshold size is less than.
+func sizeLabel(cfg plugins.SizeConfiguration, size int) string {
+	thresholds := []int{cfg.S, cfg.M, cfg.L, cfg.XL, cfg.XXL}
+	for i, def := range []int{10, 30, 100, 500, 1000} {
+		if thresholds[i] <= 0 {
+			thresholds[i] = def
+		}
+		if size < thresholds[i] {
+			return sizeLabels[i]
+		}
+	}
+	return sizeLabels[len(sizeLabels)-1]
+}
+
+// generatedRule is a pattern of .gitattributes which sets or unsets `linguist-generated`.
+type generatedRule struct {
+	glob      string
+	generated bool
+}
+
+type generatedRules []generatedRule
+
+// parseGeneratedRules parses the `linguist-generated` attributes in a .gitattributes file.
+func parseGeneratedRules(contents string) generatedRules {
+	var rules generatedRules
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			var generated bool
+			switch attr {
+			case "linguist-generated", "linguist-generated=true":
+				generated = true
+			case "-linguist-generated", "!linguist-generated", "linguist-generated=false":
+				generated = false
+			default:
+				continue
+			}
+			rules = append(rules, generatedRule{glob: gitAttributesGlob(fields[0]), generated: generated})
+		}
+	}
+	return rules
+}
+
+// match returns true if the last rule matching path marks it as generated.
+func (rules generatedRules) match(path string) bool {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if pluginhelpers.MatchGlob(rules[i].glob, path) {
+			return rules[i].generated
+		}
+	}
+	return false
+}
+
+// gitAttributesGlob converts a .gitattributes pattern to a glob relative to the repo root.
+// Patterns without a slash match at any level.
+func gitAttributesGlob(pattern string) string {
+	if strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "/") {
+		return strings.TrimPrefix(pattern, "/")
+	}
+	return "**/" + pattern
+}
@@ -0,0 +1,104 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin size", func() {
+	var (
+		prLabels      []plugins.Label
+		files         []plugins.GitCommitFile
+		cfg           plugins.SizeConfiguration
+		added         []plugins.Label
+		removed       []plugins.Label
+		gitattributes []byte
+	)
+	plugin := plugins.GetGitCommentPlugin("size", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				added = append(added, labels...)
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{Number: number, Labels: prLabels, Base: plugins.GitBranch{SHA: "base-sha"}}, nil
+			},
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return files, nil
+			},
+		}),
+		GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+			"GetFile": func(ctx context.Context, repo plugins.GitRepo, ref, path string) ([]byte, error) {
+				Expect(ref).Should(Equal("base-sha"))
+				Expect(path).Should(Equal(".gitattributes"))
+				return gitattributes, nil
+			},
+		}),
+		PluginConfigClient: mock.FakeConfigClient(func(owner, repo string) (plugins.Configuration, error) {
+			return plugins.Configuration{Size: cfg}, nil
+		}),
+	})
+	do := func(action plugins.GitCommentEventAction) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 1},
+			Action:     action,
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		prLabels, files, cfg, added, removed, gitattributes = nil, nil, plugins.SizeConfiguration{}, nil, nil, nil
+	})
+
+	It("Should label by default thresholds", func() {
+		files = []plugins.GitCommitFile{{Path: "a.go", Additions: 20, Deletions: 5}, {Path: "b.go", Additions: 4}}
+		do("opened")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "size/S"}}))
+		Expect(removed).Should(BeEmpty())
+	})
+	It("Should replace stale size labels", func() {
+		prLabels = []plugins.Label{{Name: "size/S"}, {Name: "lgtm"}}
+		files = []plugins.GitCommitFile{{Path: "a.go", Additions: 2000}}
+		do("synchronize")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "size/XXL"}}))
+		Expect(removed).Should(Equal([]plugins.Label{{Name: "size/S"}}))
+	})
+	It("Should keep the current size label", func() {
+		prLabels = []plugins.Label{{Name: "size/XS"}}
+		do("synchronize")
+		Expect(added).Should(BeEmpty())
+		Expect(removed).Should(BeEmpty())
+	})
+	It("Should use configured thresholds", func() {
+		cfg = plugins.SizeConfiguration{S: 1, M: 2, L: 3, XL: 4, XXL: 5}
+		files = []plugins.GitCommitFile{{Path: "a.go", Additions: 3}}
+		do("opened")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "size/L"}}))
+	})
+	It("Should exclude generated files", func() {
+		gitattributes = []byte("# generated\n*.pb.go linguist-generated=true\n/vendor/** linguist-generated\nvendor/keep.go -linguist-generated\n")
+		cfg = plugins.SizeConfiguration{GeneratedFiles: []string{"**/zz_generated.*.go"}}
+		files = []plugins.GitCommitFile{
+			{Path: "api/foo.pb.go", Additions: 1000},
+			{Path: "vendor/lib/lib.go", Additions: 1000},
+			{Path: "pkg/zz_generated.deepcopy.go", Additions: 1000},
+			{Path: "vendor/keep.go", Additions: 10},
+			{Path: "main.go", Deletions: 25},
+		}
+		do("opened")
+		Expect(added).Should(Equal([]plugins.Label{{Name: "size/M"}}))
+	})
+	It("Should ignore comments", func() {
+		do(plugins.GitCommentActionCreated)
+		Expect(added).Should(BeEmpty())
+	})
+})
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
 	"github.com/airconduct/kuilei/pkg/plugins"
 )
 
@@ -37,6 +38,7 @@ func init() {
 			repoClient:   cs.GitRepoClient,
 			prClient:     cs.GitPRClient,
 			searchClient: cs.GitSearchClient,
+			configClient: cs.PluginConfigClient,
 			loggerClient: cs.LoggerClient,
 		}
 		return &tideGitCommentPlugin{tidePlugin: plugin}
@@ -48,6 +50,7 @@ func init() {
 			repoClient:   cs.GitRepoClient,
 			prClient:     cs.GitPRClient,
 			searchClient: cs.GitSearchClient,
+			configClient: cs.PluginConfigClient,
 			loggerClient: cs.LoggerClient,
 		}
 		return &tideGitPRPlugin{tidePlugin: plugin}
@@ -85,6 +88,7 @@ type tidePlugin struct {
 	repoClient   plugins.GitRepoClient
 	prClient     plugins.GitPRClient
 	searchClient plugins.GitSearchClient
+	configClient plugins.PluginConfigClient
 	loggerClient plugins.LoggerClient
 
 	required    []string
@@ -108,6 +112,7 @@ func (lp *tidePlugin) BindFlags(flags *pflag.FlagSet) {
 	flags.StringSliceVar(&lp.required, "required-labels", []string{"lgtm", "approved"}, "Do not merge prs without required-labels")
 	flags.StringSliceVar(&lp.missing, "missing-labels", []string{
 		"needs-rebase", "do-not-merge/hold", "do-not-merge/work-in-progress", "do-not-merge/invalid-owners-file",
+		"do-not-merge/release-note-label-needed",
 	}, "Do not merge prs with missing-labels")
 	flags.StringVar(&lp.mergeMethod, "merge-method", "merge", "Merge method: merge | squash | rebase")
 }
@@ -122,6 +127,7 @@ func (p *tidePlugin) enqueue(key tidePRKey) {
 		RepoClient:     p.repoClient,
 		PRClient:       p.prClient,
 		SearchClient:   p.searchClient,
+		ConfigClient:   p.configClient,
 		Repo:           key.GitRepo,
 		RequiredLabels: p.required,
 		MissingLabels:  p.missing,
@@ -155,6 +161,7 @@ type tideContext struct {
 	RepoClient     plugins.GitRepoClient
 	PRClient       plugins.GitPRClient
 	SearchClient   plugins.GitSearchClient
+	ConfigClient   plugins.PluginConfigClient
 	Repo           plugins.GitRepo
 	Log            logr.Logger
 	RequiredLabels []string
@@ -162,6 +169,24 @@ type tideContext struct {
 	MergeMethod    string
 }
 
+// branches returns the globs of the base branches in `conditions.branches` of tide in the
+// config of the repo, all branches are in the pool if it is empty.
+func (tctx *tideContext) branches() ([]string, error) {
+	if tctx.ConfigClient == nil {
+		return nil, nil
+	}
+	cfg, err := tctx.ConfigClient.GetConfig(tctx.Repo.Owner.Name, tctx.Repo.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range cfg.Plugins {
+		if p.Name == "tide" {
+			return p.Conditions.Branches, nil
+		}
+	}
+	return nil, nil
+}
+
 // tideController is the controller to handle tide related events.
 // It will enqueue a tidePRKey when it receives a tide related event.
 //
@@ -261,10 +286,18 @@ func (c *tideController) syncOnce(ctx context.Context, key tidePRKey) (tideResul
 	if err != nil {
 		return tideResult{}, fmt.Errorf("failed to search prs, %w", err)
 	}
+	branches, err := tideCtx.branches()
+	if err != nil {
+		return tideResult{}, fmt.Errorf("failed to get tide branches, %w", err)
+	}
 	// Handle all prs
 	requeue := false
 	var after time.Duration
 	for _, prResult := range results {
+		// Skip the prs against branches out of the pool
+		if len(branches) > 0 && !pluginhelpers.MatchAnyGlob(branches, prResult.GitPullRequest.Base.Ref) {
+			continue
+		}
 		// Get head commit of the pr
 		commit, ok := getHeadCommit(prResult.GitPullRequest.Head, prResult.Commits)
 		// If the commit not found, skip handling
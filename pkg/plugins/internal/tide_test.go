@@ -158,4 +158,73 @@ var _ = Describe("Plugin tide", func() {
 			}, 5*time.Second, time.Second).Should(Succeed())
 		})
 	})
+
+	When("Using branches condition", func() {
+		lock := sync.Mutex{}
+		// Both prs are ready to merge, but only main is in the pool
+		prs := []plugins.GitPullRequest{
+			{Number: 1, Base: plugins.GitBranch{Ref: "main"}, Head: plugins.GitBranch{SHA: "main-sha"}},
+			{Number: 2, Base: plugins.GitBranch{Ref: "dev"}, Head: plugins.GitBranch{SHA: "dev-sha"}},
+		}
+		statuses := map[string][]plugins.GitCommitStatus{}
+		merged := map[int]bool{}
+
+		tide := plugins.GetGitCommentPlugin("tide", plugins.ClientSets{
+			GitPRClient: mock.FakeGitPRClient(nil, nil,
+				func(ctx context.Context, repo plugins.GitRepo, number int, method string) error {
+					lock.Lock()
+					defer lock.Unlock()
+					merged[number] = true
+					return nil
+				},
+			),
+			GitSearchClient: mock.FakeSearchClient(map[string]interface{}{
+				"SearchPR": func(ctx context.Context, repo plugins.GitRepo, state string) ([]plugins.GitPullRequestSearchResult, error) {
+					lock.Lock()
+					defer lock.Unlock()
+					var results []plugins.GitPullRequestSearchResult
+					for _, pr := range prs {
+						if merged[pr.Number] {
+							continue
+						}
+						pr.Labels = []plugins.Label{{Name: "lgtm"}, {Name: "approved"}}
+						commit := plugins.GitCommit{Sha: pr.Head.SHA, Statuses: append([]plugins.GitCommitStatus{}, statuses[pr.Head.SHA]...)}
+						results = append(results, plugins.GitPullRequestSearchResult{GitPullRequest: pr, Commits: []plugins.GitCommit{commit}})
+					}
+					return results, nil
+				},
+			}),
+			GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+				"CreateStatus": func(ctx context.Context, repo plugins.GitRepo, ref string, status plugins.GitCommitStatus) error {
+					lock.Lock()
+					defer lock.Unlock()
+					statuses[ref] = []plugins.GitCommitStatus{status}
+					return nil
+				},
+			}),
+			PluginConfigClient: mock.FakeConfigClient(func(owner, repo string) (plugins.Configuration, error) {
+				return plugins.Configuration{Plugins: []plugins.PluginConfiguration{{
+					Name: "tide", Conditions: plugins.PluginConditions{Branches: []string{"main", "release-*"}},
+				}}}, nil
+			}),
+			LoggerClient: mock.FakeLoggerClient(),
+		})
+
+		It("Should only merge prs against the branches", func() {
+			Expect(tide.Do(context.TODO(), plugins.GitCommentEvent{
+				GitComment: plugins.GitComment{Number: 1, IsPR: true},
+				Repo:       plugins.GitRepo{Name: "branches_repo", Owner: plugins.GitUser{Name: "foo_owner"}},
+			})).Should(Succeed())
+
+			Eventually(func(g Gomega) {
+				lock.Lock()
+				defer lock.Unlock()
+				g.Expect(merged[1]).Should(BeTrue())
+			}, 5*time.Second, 100*time.Millisecond).Should(Succeed())
+			lock.Lock()
+			defer lock.Unlock()
+			Expect(merged[2]).Should(BeFalse())
+			Expect(statuses).ShouldNot(HaveKey("dev-sha"))
+		})
+	})
 })
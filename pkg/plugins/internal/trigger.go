@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	needsOkToTestLabel = "needs-ok-to-test"
+	okToTestLabel      = "ok-to-test"
+)
+
+var okToTestRegex = regexp.MustCompile(`(?mi)^/ok-to-test[ \t]*$`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("trigger", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &triggerPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			repoClient:  cs.GitRepoClient,
+			orgClient:   cs.GitOrgClient,
+		}
+	})
+}
+
+// triggerPlugin gates the tests of pull requests from untrusted authors until a trusted user
+// comments /ok-to-test.
+type triggerPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	repoClient  plugins.GitRepoClient
+	orgClient   plugins.GitOrgClient
+
+	resetOnPush bool
+}
+
+func (tp *triggerPlugin) Name() string {
+	return "trigger"
+}
+
+func (tp *triggerPlugin) Description() string {
+	return "Labels pull requests from users who are not members or collaborators `" + needsOkToTestLabel +
+		"`, their tests are not started or re-run until a member or collaborator comments `/ok-to-test`."
+}
+
+func (tp *triggerPlugin) Usage() string {
+	return "/ok-to-test"
+}
+
+func (tp *triggerPlugin) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&tp.resetOnPush, "reset-on-push", false,
+		"require /ok-to-test again when new commits are pushed to a pull request of an untrusted author")
+}
+
+func (tp *triggerPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "opened", plugins.GitCommentActionReopened:
+		return tp.onOpened(ctx, e)
+	case "synchronize":
+		return tp.onPushed(ctx, e)
+	case plugins.GitCommentActionCreated:
+		if okToTestRegex.MatchString(plugins.CleanMarkdownComments(e.Body)) {
+			return tp.onOkToTest(ctx, e)
+		}
+	}
+	return nil
+}
+
+// onOpened labels the pull request of an untrusted author needs-ok-to-test.
+func (tp *triggerPlugin) onOpened(ctx context.Context, e plugins.GitCommentEvent) error {
+	trusted, err := plugins.IsTrusted(ctx, tp.orgClient, tp.repoClient, e.Repo, e.IssueAuthor.Name, e.AuthorAssociation)
+	if err != nil || trusted {
+		return err
+	}
+	pr, err := tp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	// A reopened pull request keeps its gate
+	if hasLabel(pr.Labels, needsOkToTestLabel) || hasLabel(pr.Labels, okToTestLabel) {
+		return nil
+	}
+	return tp.requireOkToTest(ctx, e, fmt.Sprintf(
+		"Hi @%s. Thanks for your PR.\n\n"+
+			"I'm waiting for a member or collaborator of %s to verify that this patch is reasonable to test. "+
+			"If it is, they should reply with `/ok-to-test` on its own line. "+
+			"Until that is done, tests of this pull request will not be started or re-run.",
+		e.IssueAuthor.Name, e.Repo.Owner.Name,
+	))
+}
+
+// onPushed resets the gate of an untrusted pull request if resetOnPush is set,
+// otherwise approves the tests of the new commits.
+func (tp *triggerPlugin) onPushed(ctx context.Context, e plugins.GitCommentEvent) error {
+	pr, err := tp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	if !hasLabel(pr.Labels, okToTestLabel) {
+		return nil
+	}
+	if !tp.resetOnPush {
+		return tp.repoClient.ApproveWorkflowRuns(ctx, e.Repo, pr.Head.SHA)
+	}
+	trusted, err := plugins.IsTrusted(ctx, tp.orgClient, tp.repoClient, e.Repo, pr.User.Name, e.AuthorAssociation)
+	if err != nil || trusted {
+		return err
+	}
+	issue := plugins.GitIssue{Number: e.Number}
+	if err := tp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: okToTestLabel}); err != nil {
+		return err
+	}
+	return tp.requireOkToTest(ctx, e, fmt.Sprintf(
+		"New commits are pushed by @%s, a member or collaborator of %s should verify them "+
+			"and reply with `/ok-to-test` again.",
+		pr.User.Name, e.Repo.Owner.Name,
+	))
+}
+
+// onOkToTest lifts the gate and starts the tests waiting for it, if the commenter is trusted.
+func (tp *triggerPlugin) onOkToTest(ctx context.Context, e plugins.GitCommentEvent) error {
+	issue := plugins.GitIssue{Number: e.Number}
+	trusted, err := plugins.IsTrusted(ctx, tp.orgClient, tp.repoClient, e.Repo, e.User.Name, e.AuthorAssociation)
+	if err != nil {
+		return err
+	}
+	if !trusted {
+		resp := "`/ok-to-test` is restricted to members and collaborators."
+		return tp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{
+			Body: plugins.FormatResponseRaw(e.Body, e.HTMLURL, e.User.Name, resp),
+		})
+	}
+	pr, err := tp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	if hasLabel(pr.Labels, needsOkToTestLabel) {
+		if err := tp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: needsOkToTestLabel}); err != nil {
+			return err
+		}
+	}
+	if !hasLabel(pr.Labels, okToTestLabel) {
+		if err := tp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: okToTestLabel}}); err != nil {
+			return err
+		}
+	}
+	return tp.startTests(ctx, e.Repo, pr.Head.SHA)
+}
+
+// startTests approves the workflow runs waiting for approval, and re-requests the check suites
+// of other CI apps which require action.
+func (tp *triggerPlugin) startTests(ctx context.Context, repo plugins.GitRepo, sha string) error {
+	if err := tp.repoClient.ApproveWorkflowRuns(ctx, repo, sha); err != nil {
+		return err
+	}
+	checks, err := tp.repoClient.ListChecks(ctx, repo, sha)
+	if err != nil {
+		return err
+	}
+	rerequested := map[int64]bool{}
+	for _, check := range checks {
+		if check.Conclusion != plugins.GitCheckConclusionStateActionRequired || rerequested[check.SuiteID] {
+			continue
+		}
+		rerequested[check.SuiteID] = true
+		if err := tp.repoClient.RerequestCheckSuite(ctx, repo, check.SuiteID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tp *triggerPlugin) requireOkToTest(ctx context.Context, e plugins.GitCommentEvent, comment string) error {
+	issue := plugins.GitIssue{Number: e.Number}
+	if err := tp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: needsOkToTestLabel}}); err != nil {
+		return err
+	}
+	return tp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{Body: comment})
+}
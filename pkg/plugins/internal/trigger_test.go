@@ -0,0 +1,151 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin trigger", func() {
+	var (
+		prLabels []plugins.Label
+		added    []string
+		removed  []string
+		comments []string
+		approved []string
+		suites   []int64
+	)
+	clientSets := plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				for _, l := range labels {
+					added = append(added, l.Name)
+				}
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label.Name)
+				return nil
+			},
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				comments = append(comments, comment.Body)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{
+					Number: number, User: plugins.GitUser{Name: "stranger"}, Labels: prLabels,
+					Head: plugins.GitBranch{SHA: "head-sha"},
+				}, nil
+			},
+		}),
+		GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+			"IsCollaborator": func(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+				return login == "collaborator", nil
+			},
+			"ApproveWorkflowRuns": func(ctx context.Context, repo plugins.GitRepo, sha string) error {
+				approved = append(approved, sha)
+				return nil
+			},
+			"ListChecks": func(ctx context.Context, repo plugins.GitRepo, ref string) ([]plugins.GitCommitCheck, error) {
+				return []plugins.GitCommitCheck{
+					{ID: 1, SuiteID: 10, Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateActionRequired},
+					{ID: 2, SuiteID: 10, Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateActionRequired},
+					{ID: 3, SuiteID: 20, Status: plugins.GitCheckStatusCompleted, Conclusion: plugins.GitCheckConclusionStateSuccess},
+				}, nil
+			},
+			"RerequestCheckSuite": func(ctx context.Context, repo plugins.GitRepo, id int64) error {
+				suites = append(suites, id)
+				return nil
+			},
+		}),
+		GitOrgClient: mock.FakeOrgClient(map[string]interface{}{
+			"IsOrgMember": func(ctx context.Context, org, login string) (bool, error) {
+				return false, nil
+			},
+		}),
+	}
+	plugin := plugins.GetGitCommentPlugin("trigger", clientSets)
+	prEvent := func(p plugins.GitCommentPlugin, action plugins.GitCommentEventAction, author, association string) {
+		Expect(p.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{
+				IsPR: true, Number: 12, User: plugins.GitUser{Name: author},
+				IssueAuthor: plugins.GitUser{Name: author}, AuthorAssociation: association,
+			},
+			Action: action,
+			Repo:   plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}},
+		})).Should(Succeed())
+	}
+	comment := func(user, association, body string) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{
+				IsPR: true, Number: 12, Body: body, User: plugins.GitUser{Name: user},
+				IssueAuthor: plugins.GitUser{Name: "stranger"}, AuthorAssociation: association,
+			},
+			Action: plugins.GitCommentActionCreated,
+			Repo:   plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}},
+		})).Should(Succeed())
+	}
+	BeforeEach(func() {
+		prLabels, added, removed, comments, approved, suites = nil, nil, nil, nil, nil, nil
+	})
+
+	It("Should label pull requests from untrusted authors", func() {
+		prEvent(plugin, "opened", "stranger", "FIRST_TIME_CONTRIBUTOR")
+		Expect(added).Should(Equal([]string{"needs-ok-to-test"}))
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0]).Should(ContainSubstring("Hi @stranger"))
+		Expect(comments[0]).Should(ContainSubstring("`/ok-to-test`"))
+
+		added, comments = nil, nil
+		prEvent(plugin, "opened", "member", "MEMBER")
+		prEvent(plugin, "opened", "collaborator", "CONTRIBUTOR")
+		Expect(added).Should(BeEmpty())
+		Expect(comments).Should(BeEmpty())
+
+		prLabels = []plugins.Label{{Name: "ok-to-test"}}
+		prEvent(plugin, plugins.GitCommentActionReopened, "stranger", "CONTRIBUTOR")
+		Expect(added).Should(BeEmpty())
+	})
+
+	It("Should start tests on /ok-to-test from trusted users", func() {
+		prLabels = []plugins.Label{{Name: "needs-ok-to-test"}}
+		comment("stranger", "CONTRIBUTOR", "/ok-to-test")
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0]).Should(ContainSubstring("restricted to members and collaborators"))
+		Expect(removed).Should(BeEmpty())
+		Expect(approved).Should(BeEmpty())
+
+		comment("member", "MEMBER", "/ok-to-test")
+		Expect(removed).Should(Equal([]string{"needs-ok-to-test"}))
+		Expect(added).Should(Equal([]string{"ok-to-test"}))
+		Expect(approved).Should(Equal([]string{"head-sha"}))
+		Expect(suites).Should(Equal([]int64{10}))
+	})
+
+	It("Should approve tests of new commits of ok-to-test pull requests", func() {
+		prLabels = []plugins.Label{{Name: "ok-to-test"}}
+		prEvent(plugin, "synchronize", "stranger", "CONTRIBUTOR")
+		Expect(approved).Should(Equal([]string{"head-sha"}))
+		Expect(removed).Should(BeEmpty())
+	})
+
+	It("Should reset the gate on new commits if configured", func() {
+		resetPlugin := plugins.GetGitCommentPlugin("trigger", clientSets, "--reset-on-push")
+		prEvent(resetPlugin, "synchronize", "stranger", "CONTRIBUTOR")
+		Expect(added).Should(BeEmpty())
+
+		prLabels = []plugins.Label{{Name: "ok-to-test"}}
+		prEvent(resetPlugin, "synchronize", "stranger", "CONTRIBUTOR")
+		Expect(removed).Should(Equal([]string{"ok-to-test"}))
+		Expect(added).Should(Equal([]string{"needs-ok-to-test"}))
+		Expect(comments).Should(HaveLen(1))
+		Expect(comments[0]).Should(ContainSubstring("New commits are pushed by @stranger"))
+		Expect(approved).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"github.com/airconduct/kuilei/pkg/pluginhelpers"
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	invalidOwnersLabel = "do-not-merge/invalid-owners-file"
+	// verifyOwnersMarker identifies the comment of the verify-owners plugin, which is edited
+	// when the problems change and deleted once the OWNERS files are fixed.
+	verifyOwnersMarker = "<!-- kuilei:verify-owners -->"
+)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("verify-owners", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &verifyOwnersPlugin{
+			issueClient: cs.GitIssueClient,
+			prClient:    cs.GitPRClient,
+			repoClient:  cs.GitRepoClient,
+			orgClient:   cs.GitOrgClient,
+			botClient:   cs.BotClient,
+		}
+	})
+}
+
+type verifyOwnersPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+	repoClient  plugins.GitRepoClient
+	orgClient   plugins.GitOrgClient
+	botClient   plugins.BotClient
+
+	ownersFile string
+}
+
+func (vp *verifyOwnersPlugin) Name() string {
+	return "verify-owners"
+}
+
+func (vp *verifyOwnersPlugin) Description() string {
+	return "Verifies the OWNERS files modified in a pull request, and adds `" + invalidOwnersLabel + "` label if any of them is invalid."
+}
+
+func (vp *verifyOwnersPlugin) Usage() string {
+	return "Add 'verify-owners' plugin in configuration located under [.github/kuilei.yml](/.github/kuilei.yml)"
+}
+
+func (vp *verifyOwnersPlugin) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&vp.ownersFile, "owners-file", "OWNERS", "Name of OWNERS files")
+}
+
+func (vp *verifyOwnersPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "opened", "synchronize", plugins.GitCommentActionReopened:
+	default:
+		return nil
+	}
+	pr, err := vp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	files, err := vp.prClient.ListFiles(ctx, e.Repo, pr)
+	if err != nil {
+		return err
+	}
+	var ownersFiles []string
+	for _, file := range files {
+		if file.Status != plugins.GitCommitFileStatusRemoved && path.Base(file.Path) == vp.ownersFile {
+			ownersFiles = append(ownersFiles, file.Path)
+		}
+	}
+	labeled := hasLabel(pr.Labels, invalidOwnersLabel)
+	if len(ownersFiles) == 0 && !labeled {
+		return nil
+	}
+
+	v := &ownersVerifier{plugin: vp, repo: e.Repo, checked: map[string]bool{}}
+	aliases := plugins.OwnersAliases{}
+	contents, err := vp.repoClient.GetFile(ctx, e.Repo, pr.Head.SHA, pluginhelpers.OwnersAliasesFileName)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(contents, &aliases); err != nil {
+		v.problems = append(v.problems, fmt.Sprintf("`%s`: %v", pluginhelpers.OwnersAliasesFileName, err))
+	}
+	for _, file := range ownersFiles {
+		if err := v.verify(ctx, pr.Head.SHA, file, aliases); err != nil {
+			return err
+		}
+	}
+
+	issue := plugins.GitIssue{Number: e.Number}
+	comment, err := findBotComment(ctx, vp.issueClient, vp.botClient, e.Repo, issue, verifyOwnersMarker)
+	if err != nil {
+		return err
+	}
+	if len(v.problems) == 0 {
+		if labeled {
+			if err := vp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: invalidOwnersLabel}); err != nil {
+				return err
+			}
+		}
+		if comment != nil {
+			return vp.issueClient.DeleteIssueComment(ctx, e.Repo, issue, *comment)
+		}
+		return nil
+	}
+
+	if !labeled {
+		if err := vp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: invalidOwnersLabel}}); err != nil {
+			return err
+		}
+	}
+	body := fmt.Sprintf("%s\n@%s: The following OWNERS files are invalid:\n\n", verifyOwnersMarker, pr.User.Name)
+	for _, problem := range v.problems {
+		body += fmt.Sprintf("- %s\n", problem)
+	}
+	switch {
+	case comment == nil:
+		return vp.issueClient.CreateIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{Body: body})
+	case comment.Body != body:
+		return vp.issueClient.EditIssueComment(ctx, e.Repo, issue, plugins.GitIssueComment{ID: comment.ID, Body: body})
+	}
+	return nil
+}
+
+// ownersVerifier collects the problems of OWNERS files in one pull request.
+type ownersVerifier struct {
+	plugin   *verifyOwnersPlugin
+	repo     plugins.GitRepo
+	problems []string
+	// checked caches whether a lower cased login is a collaborator or org member
+	checked map[string]bool
+}
+
+func (v *ownersVerifier) verify(ctx context.Context, ref, file string, aliases plugins.OwnersAliases) error {
+	contents, err := v.plugin.repoClient.GetFile(ctx, v.repo, ref, file)
+	if err != nil {
+		return err
+	}
+	cfg := plugins.OwnersConfiguration{}
+	if err := yaml.UnmarshalStrict(contents, &cfg); err != nil {
+		v.problems = append(v.problems, fmt.Sprintf("`%s`: %v", file, err))
+		return nil
+	}
+
+	logins := [][]string{cfg.Reviewers, cfg.Approvers, cfg.EmeritusApprovers, cfg.RequiredReviewers}
+	exprs := make([]string, 0, len(cfg.Filters))
+	for expr := range cfg.Filters {
+		exprs = append(exprs, expr)
+	}
+	sort.Strings(exprs)
+	for _, expr := range exprs {
+		filter := cfg.Filters[expr]
+		if _, err := regexp.Compile(expr); err != nil {
+			v.problems = append(v.problems, fmt.Sprintf("`%s`: invalid filter `%s`: %v", file, expr, err))
+		}
+		logins = append(logins, filter.Reviewers, filter.Approvers, filter.RequiredReviewers)
+	}
+	var invalid []string
+	for _, list := range logins {
+		for _, login := range aliases.Expand(list) {
+			if _, _, ok := plugins.ParseTeam(login); ok {
+				continue
+			}
+			valid, err := v.isKnown(ctx, login)
+			if err != nil {
+				return err
+			}
+			if !valid && !pluginhelpers.ContainsFold(invalid, login) {
+				invalid = append(invalid, login)
+			}
+		}
+	}
+	for _, login := range invalid {
+		v.problems = append(v.problems, fmt.Sprintf(
+			"`%s`: `%s` is neither a collaborator of the repo nor a member of `%s`", file, login, v.repo.Owner.Name,
+		))
+	}
+	return nil
+}
+
+// isKnown returns true if login is a collaborator of the repo or a member of its org.
+func (v *ownersVerifier) isKnown(ctx context.Context, login string) (bool, error) {
+	key := strings.ToLower(login)
+	if known, ok := v.checked[key]; ok {
+		return known, nil
+	}
+	known, err := v.plugin.repoClient.IsCollaborator(ctx, v.repo, login)
+	if err != nil {
+		return false, err
+	}
+	if !known {
+		if known, err = v.plugin.orgClient.IsOrgMember(ctx, v.repo.Owner.Name, login); err != nil {
+			return false, err
+		}
+	}
+	v.checked[key] = known
+	return known, nil
+}
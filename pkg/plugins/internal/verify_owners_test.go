@@ -0,0 +1,149 @@
+package internal_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin verify-owners", func() {
+	var (
+		prLabels []plugins.Label
+		files    []plugins.GitCommitFile
+		contents map[string]string
+		comments []plugins.GitIssueComment
+		added    []plugins.Label
+		removed  []plugins.Label
+		created  []string
+		edited   []string
+		deleted  []int
+	)
+	plugin := plugins.GetGitCommentPlugin("verify-owners", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				added = append(added, labels...)
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label)
+				return nil
+			},
+			"ListIssueComments": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) ([]plugins.GitIssueComment, error) {
+				return comments, nil
+			},
+			"CreateIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				created = append(created, comment.Body)
+				return nil
+			},
+			"EditIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				Expect(comment.ID).Should(Equal(7))
+				edited = append(edited, comment.Body)
+				return nil
+			},
+			"DeleteIssueComment": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+				deleted = append(deleted, comment.ID)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return plugins.GitPullRequest{
+					Number: number, Labels: prLabels, User: plugins.GitUser{Name: "author"},
+					Head: plugins.GitBranch{SHA: "head-sha"},
+				}, nil
+			},
+			"ListFiles": func(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
+				return files, nil
+			},
+		}),
+		GitRepoClient: mock.FakeRepoClient(map[string]interface{}{
+			"GetFile": func(ctx context.Context, repo plugins.GitRepo, ref, path string) ([]byte, error) {
+				Expect(ref).Should(Equal("head-sha"))
+				if c, ok := contents[path]; ok {
+					return []byte(c), nil
+				}
+				return nil, nil
+			},
+			"IsCollaborator": func(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+				return strings.EqualFold(login, "alice"), nil
+			},
+		}),
+		GitOrgClient: mock.FakeOrgClient(map[string]interface{}{
+			"IsOrgMember": func(ctx context.Context, org, login string) (bool, error) {
+				Expect(org).Should(Equal("airconduct"))
+				return login == "bob", nil
+			},
+		}),
+		BotClient: mock.FakeBotClient("kuilei[bot]"),
+	})
+	do := func() {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 1},
+			Action:     "synchronize",
+			Repo:       plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}},
+		})).Should(Succeed())
+	}
+	invalidLabel := plugins.Label{Name: "do-not-merge/invalid-owners-file"}
+	BeforeEach(func() {
+		prLabels, files, comments = nil, nil, nil
+		added, removed, created, edited, deleted = nil, nil, nil, nil, nil
+		contents = map[string]string{"OWNERS_ALIASES": "aliases:\n  sig-foo: [bob]\n"}
+	})
+
+	It("Should accept valid OWNERS files", func() {
+		files = []plugins.GitCommitFile{{Path: "pkg/OWNERS"}, {Path: "OWNERS", Status: plugins.GitCommitFileStatusRemoved}, {Path: "main.go"}}
+		contents["pkg/OWNERS"] = "approvers: [Alice, sig-foo, \"@airconduct/team\"]\nfilters:\n  \"\\\\.go$\":\n    reviewers: [bob]\n"
+		do()
+		Expect(added).Should(BeEmpty())
+		Expect(created).Should(BeEmpty())
+	})
+	It("Should report invalid OWNERS files", func() {
+		files = []plugins.GitCommitFile{{Path: "OWNERS"}, {Path: "pkg/OWNERS"}, {Path: "api/OWNERS"}}
+		contents["OWNERS"] = "approvers: [alice, carol]\nreviewer: [bob]\n"
+		contents["pkg/OWNERS"] = "approvers: [carol, dave, Carol]\nfilters:\n  \"(\":\n    approvers: [alice]\n"
+		contents["api/OWNERS"] = "approvers: alice: bob\n"
+		do()
+		Expect(added).Should(Equal([]plugins.Label{invalidLabel}))
+		Expect(created).Should(HaveLen(1))
+		Expect(created[0]).Should(ContainSubstring("<!-- kuilei:verify-owners -->"))
+		Expect(created[0]).Should(ContainSubstring("- `OWNERS`: error unmarshaling JSON"))
+		Expect(created[0]).Should(ContainSubstring(`unknown field "reviewer"`))
+		Expect(created[0]).Should(ContainSubstring("- `pkg/OWNERS`: invalid filter `(`"))
+		Expect(created[0]).Should(ContainSubstring("- `pkg/OWNERS`: `carol` is neither a collaborator of the repo nor a member of `airconduct`"))
+		Expect(created[0]).Should(ContainSubstring("- `pkg/OWNERS`: `dave` is neither"))
+		Expect(strings.Count(created[0], "`carol`")).Should(Equal(1))
+		Expect(created[0]).Should(ContainSubstring("- `api/OWNERS`: error converting YAML to JSON"))
+	})
+	It("Should update the existing comment", func() {
+		prLabels = []plugins.Label{invalidLabel}
+		comments = []plugins.GitIssueComment{{ID: 7, Body: "<!-- kuilei:verify-owners -->\nstale", User: plugins.GitUser{Name: "kuilei[bot]"}}}
+		files = []plugins.GitCommitFile{{Path: "OWNERS"}}
+		contents["OWNERS"] = "approvers: [carol]\n"
+		do()
+		Expect(added).Should(BeEmpty())
+		Expect(created).Should(BeEmpty())
+		Expect(edited).Should(HaveLen(1))
+		Expect(edited[0]).Should(ContainSubstring("`carol`"))
+	})
+	It("Should ignore comments spoofed by users", func() {
+		prLabels = []plugins.Label{invalidLabel}
+		comments = []plugins.GitIssueComment{{ID: 5, Body: "<!-- kuilei:verify-owners -->\nstale", User: plugins.GitUser{Name: "author"}}}
+		files = []plugins.GitCommitFile{{Path: "main.go"}}
+		do()
+		Expect(removed).Should(Equal([]plugins.Label{invalidLabel}))
+		Expect(deleted).Should(BeEmpty())
+	})
+	It("Should clear the label and comment once fixed", func() {
+		prLabels = []plugins.Label{invalidLabel}
+		comments = []plugins.GitIssueComment{{ID: 6, Body: "/lgtm"}, {ID: 7, Body: "<!-- kuilei:verify-owners -->\nstale", User: plugins.GitUser{Name: "kuilei[bot]"}}}
+		files = []plugins.GitCommitFile{{Path: "main.go"}}
+		do()
+		Expect(removed).Should(Equal([]plugins.Label{invalidLabel}))
+		Expect(deleted).Should(Equal([]int{7}))
+	})
+})
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/spf13/pflag"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const wipLabel = "do-not-merge/work-in-progress"
+
+var wipTitleRegex = regexp.MustCompile(`(?i)^\s*(\[WIP\]|WIP\b|🚧)`)
+
+func init() {
+	plugins.RegisterGitCommentPlugin("wip", func(cs plugins.ClientSets) plugins.GitCommentPlugin {
+		return &wipPlugin{issueClient: cs.GitIssueClient, prClient: cs.GitPRClient}
+	})
+}
+
+type wipPlugin struct {
+	issueClient plugins.GitIssueClient
+	prClient    plugins.GitPRClient
+}
+
+func (wp *wipPlugin) Name() string {
+	return "wip"
+}
+
+func (wp *wipPlugin) Description() string {
+	return "Adds `" + wipLabel + "` label to draft pull requests and pull requests whose title starts with `WIP`, `[WIP]` or `🚧`."
+}
+
+func (wp *wipPlugin) Usage() string {
+	return "Add 'wip' plugin in configuration located under [.github/kuilei.yml](/.github/kuilei.yml)"
+}
+
+func (wp *wipPlugin) BindFlags(flags *pflag.FlagSet) {}
+
+func (wp *wipPlugin) Do(ctx context.Context, e plugins.GitCommentEvent) error {
+	if !e.IsPR {
+		return nil
+	}
+	switch e.Action {
+	case "opened", plugins.GitCommentActionReopened, plugins.GitCommentActionEdited, "ready_for_review", "converted_to_draft":
+	default:
+		return nil
+	}
+	pr, err := wp.prClient.GetPR(ctx, e.Repo, e.Number)
+	if err != nil {
+		return err
+	}
+	wip := pr.Draft || wipTitleRegex.MatchString(pr.Title)
+	issue := plugins.GitIssue{Number: e.Number}
+	switch has := hasLabel(pr.Labels, wipLabel); {
+	case wip && !has:
+		return wp.issueClient.AddLabel(ctx, e.Repo, issue, []plugins.Label{{Name: wipLabel}})
+	case !wip && has:
+		return wp.issueClient.RemoveLabel(ctx, e.Repo, issue, plugins.Label{Name: wipLabel})
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package internal_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("Plugin wip", func() {
+	var (
+		pr      plugins.GitPullRequest
+		added   []plugins.Label
+		removed []plugins.Label
+	)
+	plugin := plugins.GetGitCommentPlugin("wip", plugins.ClientSets{
+		GitIssueClient: mock.FakeIssueClient(map[string]interface{}{
+			"AddLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
+				added = append(added, labels...)
+				return nil
+			},
+			"RemoveLabel": func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, label plugins.Label) error {
+				removed = append(removed, label)
+				return nil
+			},
+		}),
+		GitPRClient: mock.FakePRClient(map[string]interface{}{
+			"GetPR": func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
+				return pr, nil
+			},
+		}),
+	})
+	do := func(action plugins.GitCommentEventAction) {
+		Expect(plugin.Do(context.TODO(), plugins.GitCommentEvent{
+			GitComment: plugins.GitComment{IsPR: true, Number: 1},
+			Action:     action,
+		})).Should(Succeed())
+	}
+	wipLabel := plugins.Label{Name: "do-not-merge/work-in-progress"}
+	BeforeEach(func() {
+		pr, added, removed = plugins.GitPullRequest{Number: 1}, nil, nil
+	})
+
+	It("Should label pull requests with WIP titles", func() {
+		for _, title := range []string{"WIP: foo", "[WIP] foo", "wip foo", "🚧 foo"} {
+			added = nil
+			pr.Title = title
+			do("opened")
+			Expect(added).Should(Equal([]plugins.Label{wipLabel}), title)
+		}
+	})
+	It("Should label draft pull requests", func() {
+		pr.Title, pr.Draft = "foo", true
+		do("converted_to_draft")
+		Expect(added).Should(Equal([]plugins.Label{wipLabel}))
+	})
+	It("Should not label titles mentioning WIP elsewhere", func() {
+		pr.Title = "Wipe caches, not WIP"
+		do(plugins.GitCommentActionEdited)
+		Expect(added).Should(BeEmpty())
+	})
+	It("Should remove the label when ready for review", func() {
+		pr.Title, pr.Labels = "foo", []plugins.Label{wipLabel}
+		do("ready_for_review")
+		Expect(removed).Should(Equal([]plugins.Label{wipLabel}))
+		Expect(added).Should(BeEmpty())
+	})
+	It("Should ignore comments", func() {
+		pr.Title = "WIP foo"
+		do(plugins.GitCommentActionCreated)
+		Expect(added).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+func FakeBotClient(login string) plugins.BotClient {
+	return &fakeBotClient{login: login}
+}
+
+type fakeBotClient struct {
+	login string
+}
+
+func (c *fakeBotClient) BotUser(ctx context.Context) (plugins.GitUser, error) {
+	return plugins.GitUser{Name: c.login}, nil
+}
@@ -18,20 +18,96 @@ func FakeGitIssueClient(
 	}
 }
 
+// FakeIssueClient returns a GitIssueClient whose methods call the funcs of the same name.
+func FakeIssueClient(funcs map[string]interface{}) plugins.GitIssueClient {
+	return &fakeIssueClient{funcs: funcs}
+}
+
 type fakeIssueClient struct {
 	createIssueComment func(context.Context, plugins.GitIssueComment) error
 	addLabel           func(context.Context, []plugins.Label) error
 	removeLabel        func(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, l plugins.Label) error
+
+	funcs map[string]interface{}
 }
 
 func (c *fakeIssueClient) CreateIssueComment(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
-	return c.createIssueComment(ctx, comment)
+	if c.createIssueComment != nil {
+		return c.createIssueComment(ctx, comment)
+	}
+	return c.funcs["CreateIssueComment"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, plugins.GitIssueComment) error)(
+		ctx, repo, issue, comment,
+	)
+}
+
+func (c *fakeIssueClient) EditIssueComment(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+	return c.funcs["EditIssueComment"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, plugins.GitIssueComment) error)(
+		ctx, repo, issue, comment,
+	)
+}
+
+func (c *fakeIssueClient) DeleteIssueComment(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, comment plugins.GitIssueComment) error {
+	return c.funcs["DeleteIssueComment"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, plugins.GitIssueComment) error)(
+		ctx, repo, issue, comment,
+	)
 }
 
 func (c *fakeIssueClient) AddLabel(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, labels []plugins.Label) error {
-	return c.addLabel(ctx, labels)
+	if c.addLabel != nil {
+		return c.addLabel(ctx, labels)
+	}
+	return c.funcs["AddLabel"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, []plugins.Label) error)(
+		ctx, repo, issue, labels,
+	)
 }
 
 func (c *fakeIssueClient) RemoveLabel(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, l plugins.Label) error {
-	return c.removeLabel(ctx, repo, issue, l)
+	if c.removeLabel != nil {
+		return c.removeLabel(ctx, repo, issue, l)
+	}
+	return c.funcs["RemoveLabel"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, plugins.Label) error)(
+		ctx, repo, issue, l,
+	)
+}
+
+func (c *fakeIssueClient) ListIssueComments(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) ([]plugins.GitIssueComment, error) {
+	return c.funcs["ListIssueComments"].(func(context.Context, plugins.GitRepo, plugins.GitIssue) ([]plugins.GitIssueComment, error))(
+		ctx, repo, issue,
+	)
+}
+
+func (c *fakeIssueClient) AddAssignees(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, logins []string) error {
+	return c.funcs["AddAssignees"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, []string) error)(
+		ctx, repo, issue, logins,
+	)
+}
+
+func (c *fakeIssueClient) RemoveAssignees(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, logins []string) error {
+	return c.funcs["RemoveAssignees"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, []string) error)(
+		ctx, repo, issue, logins,
+	)
+}
+
+func (c *fakeIssueClient) CloseIssue(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, reason plugins.GitIssueCloseReason) error {
+	return c.funcs["CloseIssue"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, plugins.GitIssueCloseReason) error)(
+		ctx, repo, issue, reason,
+	)
+}
+
+func (c *fakeIssueClient) ReopenIssue(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue) error {
+	return c.funcs["ReopenIssue"].(func(context.Context, plugins.GitRepo, plugins.GitIssue) error)(
+		ctx, repo, issue,
+	)
+}
+
+func (c *fakeIssueClient) ListMilestones(ctx context.Context, repo plugins.GitRepo) ([]plugins.GitMilestone, error) {
+	return c.funcs["ListMilestones"].(func(context.Context, plugins.GitRepo) ([]plugins.GitMilestone, error))(
+		ctx, repo,
+	)
+}
+
+func (c *fakeIssueClient) SetMilestone(ctx context.Context, repo plugins.GitRepo, issue plugins.GitIssue, milestone int) error {
+	return c.funcs["SetMilestone"].(func(context.Context, plugins.GitRepo, plugins.GitIssue, int) error)(
+		ctx, repo, issue, milestone,
+	)
 }
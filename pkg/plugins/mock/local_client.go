@@ -0,0 +1,21 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+func FakeLocalClient(funcs map[string]interface{}) plugins.GitLocalClient {
+	return &fakeLocalClient{funcs: funcs}
+}
+
+type fakeLocalClient struct {
+	funcs map[string]interface{}
+}
+
+func (c *fakeLocalClient) CherryPick(ctx context.Context, repo plugins.GitRepo, opts plugins.GitCherryPickOptions) ([]string, error) {
+	return c.funcs["CherryPick"].(func(context.Context, plugins.GitRepo, plugins.GitCherryPickOptions) ([]string, error))(
+		ctx, repo, opts,
+	)
+}
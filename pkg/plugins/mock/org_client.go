@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+func FakeOrgClient(funcs map[string]interface{}) plugins.GitOrgClient {
+	return &fakeOrgClient{funcs: funcs}
+}
+
+type fakeOrgClient struct {
+	funcs map[string]interface{}
+}
+
+func (c *fakeOrgClient) IsTeamMember(ctx context.Context, org, team, login string) (bool, error) {
+	return c.funcs["IsTeamMember"].(func(ctx context.Context, org, team, login string) (bool, error))(
+		ctx, org, team, login,
+	)
+}
+
+func (c *fakeOrgClient) IsOrgMember(ctx context.Context, org, login string) (bool, error) {
+	return c.funcs["IsOrgMember"].(func(ctx context.Context, org, login string) (bool, error))(
+		ctx, org, login,
+	)
+}
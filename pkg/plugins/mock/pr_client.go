@@ -18,20 +18,66 @@ func FakeGitPRClient(
 	}
 }
 
+// FakePRClient returns a GitPRClient whose methods call the funcs of the same name.
+func FakePRClient(funcs map[string]interface{}) plugins.GitPRClient {
+	return &fakePRClient{funcs: funcs}
+}
+
 type fakePRClient struct {
 	listFiles func(context.Context, plugins.GitRepo, plugins.GitPullRequest) ([]plugins.GitCommitFile, error)
 	getPR     func(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error)
 	mergePR   func(ctx context.Context, repo plugins.GitRepo, number int, method string) error
+
+	funcs map[string]interface{}
 }
 
 func (c *fakePRClient) ListFiles(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) ([]plugins.GitCommitFile, error) {
-	return c.listFiles(ctx, repo, pr)
+	if c.listFiles != nil {
+		return c.listFiles(ctx, repo, pr)
+	}
+	return c.funcs["ListFiles"].(func(context.Context, plugins.GitRepo, plugins.GitPullRequest) ([]plugins.GitCommitFile, error))(
+		ctx, repo, pr,
+	)
+}
+
+func (c *fakePRClient) ListCommits(ctx context.Context, repo plugins.GitRepo, number int) ([]plugins.GitCommit, error) {
+	return c.funcs["ListCommits"].(func(context.Context, plugins.GitRepo, int) ([]plugins.GitCommit, error))(
+		ctx, repo, number,
+	)
 }
 
 func (c *fakePRClient) GetPR(ctx context.Context, repo plugins.GitRepo, number int) (plugins.GitPullRequest, error) {
-	return c.getPR(ctx, repo, number)
+	if c.getPR != nil {
+		return c.getPR(ctx, repo, number)
+	}
+	return c.funcs["GetPR"].(func(context.Context, plugins.GitRepo, int) (plugins.GitPullRequest, error))(
+		ctx, repo, number,
+	)
 }
 
 func (c *fakePRClient) MergePR(ctx context.Context, repo plugins.GitRepo, number int, method string) error {
-	return c.mergePR(ctx, repo, number, method)
+	if c.mergePR != nil {
+		return c.mergePR(ctx, repo, number, method)
+	}
+	return c.funcs["MergePR"].(func(context.Context, plugins.GitRepo, int, string) error)(
+		ctx, repo, number, method,
+	)
+}
+
+func (c *fakePRClient) RequestReviewers(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+	return c.funcs["RequestReviewers"].(func(context.Context, plugins.GitRepo, int, []string) error)(
+		ctx, repo, number, reviewers,
+	)
+}
+
+func (c *fakePRClient) RemoveReviewers(ctx context.Context, repo plugins.GitRepo, number int, reviewers []string) error {
+	return c.funcs["RemoveReviewers"].(func(context.Context, plugins.GitRepo, int, []string) error)(
+		ctx, repo, number, reviewers,
+	)
+}
+
+func (c *fakePRClient) CreatePR(ctx context.Context, repo plugins.GitRepo, pr plugins.GitPullRequest) (int, error) {
+	return c.funcs["CreatePR"].(func(context.Context, plugins.GitRepo, plugins.GitPullRequest) (int, error))(
+		ctx, repo, pr,
+	)
 }
@@ -31,3 +31,33 @@ func (c *fakeRepoClient) ListChecks(ctx context.Context, repo plugins.GitRepo, r
 		ctx, repo, ref,
 	)
 }
+
+func (c *fakeRepoClient) IsCollaborator(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+	return c.funcs["IsCollaborator"].(func(ctx context.Context, repo plugins.GitRepo, login string) (bool, error))(
+		ctx, repo, login,
+	)
+}
+
+func (c *fakeRepoClient) RerequestCheckSuite(ctx context.Context, repo plugins.GitRepo, suiteID int64) error {
+	return c.funcs["RerequestCheckSuite"].(func(ctx context.Context, repo plugins.GitRepo, suiteID int64) error)(
+		ctx, repo, suiteID,
+	)
+}
+
+func (c *fakeRepoClient) RerequestCheckRun(ctx context.Context, repo plugins.GitRepo, runID int64) error {
+	return c.funcs["RerequestCheckRun"].(func(ctx context.Context, repo plugins.GitRepo, runID int64) error)(
+		ctx, repo, runID,
+	)
+}
+
+func (c *fakeRepoClient) ApproveWorkflowRuns(ctx context.Context, repo plugins.GitRepo, headSHA string) error {
+	return c.funcs["ApproveWorkflowRuns"].(func(ctx context.Context, repo plugins.GitRepo, headSHA string) error)(
+		ctx, repo, headSHA,
+	)
+}
+
+func (c *fakeRepoClient) GetFile(ctx context.Context, repo plugins.GitRepo, ref, path string) ([]byte, error) {
+	return c.funcs["GetFile"].(func(ctx context.Context, repo plugins.GitRepo, ref, path string) ([]byte, error))(
+		ctx, repo, ref, path,
+	)
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/airconduct/kuilei/pkg/plugins"
 )
@@ -19,3 +20,21 @@ func (c *fakeSearchClient) SearchPR(ctx context.Context, repo plugins.GitRepo, s
 		ctx, repo, state,
 	)
 }
+
+func (c *fakeSearchClient) SearchMergedPR(ctx context.Context, repo plugins.GitRepo, base string, since, until time.Time) ([]plugins.GitPullRequest, error) {
+	return c.funcs["SearchMergedPR"].(func(ctx context.Context, repo plugins.GitRepo, base string, since, until time.Time) ([]plugins.GitPullRequest, error))(
+		ctx, repo, base, since, until,
+	)
+}
+
+func (c *fakeSearchClient) ListMergedPRBetween(ctx context.Context, repo plugins.GitRepo, from, to string) ([]plugins.GitPullRequest, error) {
+	return c.funcs["ListMergedPRBetween"].(func(ctx context.Context, repo plugins.GitRepo, from, to string) ([]plugins.GitPullRequest, error))(
+		ctx, repo, from, to,
+	)
+}
+
+func (c *fakeSearchClient) SearchIssues(ctx context.Context, repo plugins.GitRepo, query string) ([]plugins.GitIssue, error) {
+	return c.funcs["SearchIssues"].(func(ctx context.Context, repo plugins.GitRepo, query string) ([]plugins.GitIssue, error))(
+		ctx, repo, query,
+	)
+}
@@ -0,0 +1,101 @@
+package plugins
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// OwnersChainItem is an OWNERS file in the parent chain of a file.
+type OwnersChainItem struct {
+	// Dir is the directory of the OWNERS file relative to the repo root, "" for the root.
+	Dir    string
+	Config *OwnersConfiguration
+}
+
+// ResolveOwners combines the owners of file from its OWNERS parent chain, which is ordered
+// from the nearest OWNERS file to the root one. Walking up stops at the first OWNERS file
+// with `options.no_parent_owners`. The path of the result is the directory of the nearest
+// OWNERS file, and its options are the ones of the nearest OWNERS file.
+func ResolveOwners(file string, chain []OwnersChainItem) OwnersConfiguration {
+	out := OwnersConfiguration{}
+	if len(chain) == 0 {
+		return out
+	}
+	out.Owner, out.Repo = chain[0].Config.Owner, chain[0].Config.Repo
+	out.Path, out.Options = chain[0].Dir, chain[0].Config.Options
+
+	reviewers, approvers := newLoginSet(), newLoginSet()
+	labels, emeritus, required := newLoginSet(), newLoginSet(), newLoginSet()
+	for _, item := range chain {
+		cfg := item.Config
+		reviewers.insert(cfg.Reviewers...)
+		approvers.insert(cfg.Approvers...)
+		labels.insert(cfg.Labels...)
+		emeritus.insert(cfg.EmeritusApprovers...)
+		required.insert(cfg.RequiredReviewers...)
+		relative := strings.TrimPrefix(strings.TrimPrefix(path.Clean(file), item.Dir), "/")
+		for expr, filter := range cfg.Filters {
+			re, err := regexp.Compile(expr)
+			if err != nil || !re.MatchString(relative) {
+				continue
+			}
+			reviewers.insert(filter.Reviewers...)
+			approvers.insert(filter.Approvers...)
+			labels.insert(filter.Labels...)
+			required.insert(filter.RequiredReviewers...)
+		}
+		if cfg.Options.NoParentOwners {
+			break
+		}
+	}
+	out.Reviewers, out.Approvers = reviewers.list, approvers.list
+	out.Labels, out.EmeritusApprovers, out.RequiredReviewers = labels.list, emeritus.list, required.list
+	return out
+}
+
+// Expand replaces the aliases in logins with their members, duplicates are removed.
+// Alias names are case insensitive.
+func (a OwnersAliases) Expand(logins []string) []string {
+	aliases := make(map[string][]string, len(a.Aliases))
+	for name, members := range a.Aliases {
+		aliases[strings.ToLower(name)] = members
+	}
+	out := newLoginSet()
+	for _, login := range logins {
+		if members, ok := aliases[strings.ToLower(login)]; ok {
+			out.insert(members...)
+			continue
+		}
+		out.insert(login)
+	}
+	return out.list
+}
+
+// ParseTeam returns the org and slug of a "@org/team-slug" entry of OWNERS files.
+func ParseTeam(login string) (org, slug string, ok bool) {
+	if !strings.HasPrefix(login, "@") {
+		return "", "", false
+	}
+	org, slug, ok = strings.Cut(strings.TrimPrefix(login, "@"), "/")
+	return org, slug, ok && org != "" && slug != ""
+}
+
+// loginSet keeps the insertion order and ignores duplicates case insensitively.
+type loginSet struct {
+	seen map[string]bool
+	list []string
+}
+
+func newLoginSet() *loginSet {
+	return &loginSet{seen: map[string]bool{}}
+}
+
+func (s *loginSet) insert(values ...string) {
+	for _, v := range values {
+		if key := strings.ToLower(v); !s.seen[key] {
+			s.seen[key] = true
+			s.list = append(s.list, v)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package plugins_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+var _ = Describe("ResolveOwners", func() {
+	root := &plugins.OwnersConfiguration{
+		Reviewers: []string{"root-reviewer"},
+		Approvers: []string{"root-approver"},
+		Labels:    []string{"area/root"},
+	}
+	pkg := &plugins.OwnersConfiguration{
+		Reviewers:         []string{"pkg-reviewer", "ROOT-reviewer"},
+		Approvers:         []string{"pkg-approver"},
+		EmeritusApprovers: []string{"pkg-emeritus"},
+		Filters: map[string]plugins.OwnersFilter{
+			`\.go$`:   {Approvers: []string{"go-approver"}, Labels: []string{"lang/go"}},
+			`^docs/`:  {Reviewers: []string{"docs-reviewer"}},
+			`^pkg/.*`: {Reviewers: []string{"never"}},
+		},
+	}
+	vendor := &plugins.OwnersConfiguration{
+		Options:   plugins.OwnersOptions{NoParentOwners: true},
+		Approvers: []string{"vendor-approver"},
+	}
+
+	It("Should combine owners of the parent chain", func() {
+		cfg := plugins.ResolveOwners("pkg/foo/bar.go", []plugins.OwnersChainItem{
+			{Dir: "pkg", Config: pkg}, {Dir: "", Config: root},
+		})
+		Expect(cfg.Path).Should(Equal("pkg"))
+		Expect(cfg.Reviewers).Should(Equal([]string{"pkg-reviewer", "ROOT-reviewer"}))
+		Expect(cfg.Approvers).Should(ConsistOf("pkg-approver", "go-approver", "root-approver"))
+		Expect(cfg.Labels).Should(ConsistOf("lang/go", "area/root"))
+		Expect(cfg.EmeritusApprovers).Should(Equal([]string{"pkg-emeritus"}))
+	})
+	It("Should match filters against the path relative to the OWNERS file", func() {
+		cfg := plugins.ResolveOwners("pkg/docs/README.md", []plugins.OwnersChainItem{
+			{Dir: "pkg", Config: pkg}, {Dir: "", Config: root},
+		})
+		Expect(cfg.Reviewers).Should(ContainElement("docs-reviewer"))
+		Expect(cfg.Reviewers).ShouldNot(ContainElement("never"))
+		Expect(cfg.Approvers).ShouldNot(ContainElement("go-approver"))
+	})
+	It("Should stop at no_parent_owners", func() {
+		cfg := plugins.ResolveOwners("vendor/foo.go", []plugins.OwnersChainItem{
+			{Dir: "vendor", Config: vendor}, {Dir: "", Config: root},
+		})
+		Expect(cfg.Approvers).Should(Equal([]string{"vendor-approver"}))
+		Expect(cfg.Reviewers).Should(BeEmpty())
+		Expect(cfg.Options.NoParentOwners).Should(BeTrue())
+	})
+	It("Should get empty owners", func() {
+		Expect(plugins.ResolveOwners("foo", nil)).Should(Equal(plugins.OwnersConfiguration{}))
+	})
+})
+
+var _ = Describe("OwnersAliases", func() {
+	aliases := plugins.OwnersAliases{Aliases: map[string][]string{
+		"sig-foo": {"alice", "bob"},
+		"sig-bar": {"bob", "@airconduct/bar"},
+	}}
+	It("Should expand aliases", func() {
+		Expect(aliases.Expand([]string{"SIG-foo", "carol", "sig-bar", "Alice"})).
+			Should(Equal([]string{"alice", "bob", "carol", "@airconduct/bar"}))
+	})
+	It("Should parse teams", func() {
+		org, slug, ok := plugins.ParseTeam("@airconduct/bar")
+		Expect(ok).Should(BeTrue())
+		Expect(org).Should(Equal("airconduct"))
+		Expect(slug).Should(Equal("bar"))
+		_, _, ok = plugins.ParseTeam("bar")
+		Expect(ok).Should(BeFalse())
+		_, _, ok = plugins.ParseTeam("@airconduct")
+		Expect(ok).Should(BeFalse())
+	})
+})
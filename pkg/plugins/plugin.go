@@ -62,3 +62,35 @@ func GetGitPRPlugin(name string, clientSets ClientSets, args ...string) GitPRPlu
 	}
 	return nil
 }
+
+// Periodic plugin
+type PeriodicPluginBuilder func(ClientSets) PeriodicPlugin
+
+// PeriodicPlugin runs on the schedule of its plugin config instead of on events.
+type PeriodicPlugin interface {
+	Plugin
+	Run(context.Context, GitRepo) error
+}
+
+var periodicPlugins = map[string]PeriodicPluginBuilder{}
+
+func RegisterPeriodicPlugin(name string, builder PeriodicPluginBuilder) {
+	periodicPlugins[name] = builder
+}
+
+func GetPeriodicPlugin(name string, clientSets ClientSets, args ...string) PeriodicPlugin {
+	if builder, ok := periodicPlugins[name]; ok {
+		p := builder(clientSets)
+		flags := pflag.NewFlagSet(p.Name(), pflag.ContinueOnError)
+		p.BindFlags(flags)
+		flags.Parse(args)
+		return p
+	}
+	return nil
+}
+
+// IsPeriodicPlugin returns true if a periodic plugin is registered with the name.
+func IsPeriodicPlugin(name string) bool {
+	_, ok := periodicPlugins[name]
+	return ok
+}
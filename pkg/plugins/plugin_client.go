@@ -2,6 +2,7 @@ package plugins
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 )
@@ -11,32 +12,88 @@ type ClientSets struct {
 	GitPRClient
 	GitRepoClient
 	GitSearchClient
+	GitOrgClient
+	GitLocalClient
 
 	PluginConfigClient
 	OwnersClient
 	LoggerClient
+	BotClient
 }
 
 type GitIssueClient interface {
 	CreateIssueComment(context.Context, GitRepo, GitIssue, GitIssueComment) error
+	// EditIssueComment replaces the body of the comment with the ID of GitIssueComment.
+	EditIssueComment(context.Context, GitRepo, GitIssue, GitIssueComment) error
+	// DeleteIssueComment deletes the comment with the ID of GitIssueComment.
+	DeleteIssueComment(context.Context, GitRepo, GitIssue, GitIssueComment) error
 	AddLabel(context.Context, GitRepo, GitIssue, []Label) error
 	RemoveLabel(context.Context, GitRepo, GitIssue, Label) error
+	// ListIssueComments lists all comments of an issue or pull request, oldest first.
+	ListIssueComments(context.Context, GitRepo, GitIssue) ([]GitIssueComment, error)
+	AddAssignees(ctx context.Context, repo GitRepo, issue GitIssue, logins []string) error
+	RemoveAssignees(ctx context.Context, repo GitRepo, issue GitIssue, logins []string) error
+	// CloseIssue closes an issue or pull request, reason is one of the GitIssueCloseReason values
+	// and it is ignored for pull requests.
+	CloseIssue(ctx context.Context, repo GitRepo, issue GitIssue, reason GitIssueCloseReason) error
+	ReopenIssue(ctx context.Context, repo GitRepo, issue GitIssue) error
+	// ListMilestones lists the open milestones of a repo.
+	ListMilestones(ctx context.Context, repo GitRepo) ([]GitMilestone, error)
+	// SetMilestone sets the milestone of an issue or pull request, zero clears the milestone.
+	SetMilestone(ctx context.Context, repo GitRepo, issue GitIssue, milestone int) error
 }
 
 type GitPRClient interface {
 	ListFiles(context.Context, GitRepo, GitPullRequest) ([]GitCommitFile, error)
+	// ListCommits lists the commits of a pull request, oldest first.
+	ListCommits(ctx context.Context, repo GitRepo, number int) ([]GitCommit, error)
 	GetPR(ctx context.Context, repo GitRepo, number int) (GitPullRequest, error)
 	MergePR(ctx context.Context, repo GitRepo, number int, method string) error
+	RequestReviewers(ctx context.Context, repo GitRepo, number int, reviewers []string) error
+	RemoveReviewers(ctx context.Context, repo GitRepo, number int, reviewers []string) error
+	// CreatePR opens a pull request from Head.Ref to Base.Ref and returns its number.
+	CreatePR(ctx context.Context, repo GitRepo, pr GitPullRequest) (int, error)
 }
 
 type GitRepoClient interface {
 	CreateStatus(ctx context.Context, repo GitRepo, ref string, status GitCommitStatus) error
 	ListStatuses(ctx context.Context, repo GitRepo, ref string) ([]GitCommitStatus, error)
 	ListChecks(ctx context.Context, repo GitRepo, ref string) ([]GitCommitCheck, error)
+	IsCollaborator(ctx context.Context, repo GitRepo, login string) (bool, error)
+	RerequestCheckSuite(ctx context.Context, repo GitRepo, suiteID int64) error
+	RerequestCheckRun(ctx context.Context, repo GitRepo, runID int64) error
+	// ApproveWorkflowRuns approves the workflow runs of headSHA which wait for approval,
+	// as required for pull requests from outside collaborators if the repo is configured so.
+	ApproveWorkflowRuns(ctx context.Context, repo GitRepo, headSHA string) error
+	// GetFile returns the contents of a file at ref, nil without error if the file does not exist.
+	GetFile(ctx context.Context, repo GitRepo, ref, path string) ([]byte, error)
 }
 
 type GitSearchClient interface {
 	SearchPR(ctx context.Context, repo GitRepo, state string) ([]GitPullRequestSearchResult, error)
+	// SearchMergedPR lists pull requests into base merged in [since, until), oldest first.
+	// An empty base matches all branches and a zero until means now.
+	SearchMergedPR(ctx context.Context, repo GitRepo, base string, since, until time.Time) ([]GitPullRequest, error)
+	// ListMergedPRBetween lists pull requests merged by the commits in from...to, oldest first.
+	// from is a branch or tag, and an empty to means the default branch.
+	ListMergedPRBetween(ctx context.Context, repo GitRepo, from, to string) ([]GitPullRequest, error)
+	// SearchIssues lists the issues and pull requests of repo matching the GitHub search query,
+	// e.g. "is:open label:bug".
+	SearchIssues(ctx context.Context, repo GitRepo, query string) ([]GitIssue, error)
+}
+
+type GitOrgClient interface {
+	IsTeamMember(ctx context.Context, org, team, login string) (bool, error)
+	// IsOrgMember returns false without error if org is not an organization.
+	IsOrgMember(ctx context.Context, org, login string) (bool, error)
+}
+
+// GitLocalClient works on local clones of repos.
+type GitLocalClient interface {
+	// CherryPick applies the commits in BaseSHA..HeadSHA onto the Onto branch and pushes the
+	// result as Branch. If a commit cannot be applied, the conflicting files are returned and
+	// nothing is pushed.
+	CherryPick(ctx context.Context, repo GitRepo, opts GitCherryPickOptions) (conflicts []string, err error)
 }
 
 type PluginConfigClient interface {
@@ -50,3 +107,8 @@ type OwnersClient interface {
 type LoggerClient interface {
 	GetLogger() logr.Logger
 }
+
+type BotClient interface {
+	// BotUser returns the user the App acts as, e.g. "kuilei[bot]".
+	BotUser(ctx context.Context) (GitUser, error)
+}
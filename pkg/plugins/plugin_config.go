@@ -6,20 +6,169 @@ package plugins
 //	  label:
 //	  - --known-values=aaa,bbb,ccc
 type Configuration struct {
-	Owner   string                `json:"owner"`
-	Repo    string                `json:"repo"`
-	Plugins []PluginConfiguration `json:"plugins"`
+	Owner       string                   `json:"owner"`
+	Repo        string                   `json:"repo"`
+	Plugins     []PluginConfiguration    `json:"plugins"`
+	Blunderbuss BlunderbussConfiguration `json:"blunderbuss,omitempty"`
+	Size        SizeConfiguration        `json:"size,omitempty"`
+	Labeler     []LabelerRule            `json:"labeler,omitempty"`
+	Lifecycle   LifecycleConfiguration   `json:"lifecycle,omitempty"`
+}
+
+// BlunderbussConfiguration configures the blunderbuss plugin.
+//
+//	blunderbuss:
+//	  busy_users: ["alice"]
+type BlunderbussConfiguration struct {
+	// BusyUsers are not requested for review when the plugin skips busy users.
+	BusyUsers []string `json:"busy_users,omitempty"`
+}
+
+// SizeConfiguration configures the size plugin. A pull request gets the label of the first
+// threshold its changed lines are less than, e.g. `size/S` for less than M lines, and
+// `size/XXL` if none. Zero thresholds take the defaults 10, 30, 100, 500 and 1000.
+//
+//	size:
+//	  s: 10
+//	  m: 30
+//	  l: 100
+//	  xl: 500
+//	  xxl: 1000
+//	  generated_files: ["**/zz_generated.*.go"]
+type SizeConfiguration struct {
+	S   int `json:"s,omitempty"`
+	M   int `json:"m,omitempty"`
+	L   int `json:"l,omitempty"`
+	XL  int `json:"xl,omitempty"`
+	XXL int `json:"xxl,omitempty"`
+	// GeneratedFiles are globs of files which are not counted, in addition to the files
+	// marked as `linguist-generated` in .gitattributes.
+	GeneratedFiles []string `json:"generated_files,omitempty"`
+}
+
+// LabelerRule configures a label of the labeler plugin. A glob prefixed with "!" is negated,
+// a file matches the globs if it matches any of the globs and none of the negated ones.
+// The label is added if every non-empty field matches, and removed otherwise.
+//
+//	labeler:
+//	- label: area/docs
+//	  any: ["docs/**", "!docs/internal/**"]
+//	- label: docs-only
+//	  all: ["**/*.md"]
+type LabelerRule struct {
+	Label string `json:"label"`
+	// Any matches if at least one changed file matches the globs.
+	Any []string `json:"any,omitempty"`
+	// All matches if all changed files match the globs.
+	All []string `json:"all,omitempty"`
+}
+
+// LifecycleConfiguration configures the lifecycle plugin. Open issues and pull requests are
+// labeled `lifecycle/stale` after StaleDays without activity, then `lifecycle/rotten` after
+// RottenDays more, and closed after CloseDays more. Zero values take the defaults 90, 30 and 30.
+//
+//	lifecycle:
+//	  stale_days: 90
+//	  rotten_days: 30
+//	  close_days: 30
+type LifecycleConfiguration struct {
+	StaleDays  int `json:"stale_days,omitempty"`
+	RottenDays int `json:"rotten_days,omitempty"`
+	CloseDays  int `json:"close_days,omitempty"`
 }
 
 type PluginConfiguration struct {
-	Name string   `json:"name"`
-	Args []string `json:"args"`
+	Name       string           `json:"name"`
+	Args       []string         `json:"args"`
+	Conditions PluginConditions `json:"conditions,omitempty"`
+	// Schedule is the schedule of a periodic plugin, ignored by other plugins. It is a cron
+	// expression in UTC, e.g. "30 2 * * 1-5", a descriptor like "@hourly" and "@daily",
+	// or "@every <duration>" of at least 1m. Defaults to "@hourly".
+	//
+	//	plugins:
+	//	- name: lifecycle
+	//	  schedule: "0 */6 * * *"
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// PluginConditions restricts the events a plugin is executed for.
+// Every non-empty field must match, an empty field matches everything.
+// Tide also limits its merge pool to the pull requests against the branches.
+//
+//	plugins:
+//	- name: tide
+//	  conditions:
+//	    branches: ["main", "release-*"]
+//	- name: approve
+//	  conditions:
+//	    events: ["issue_comment"]
+//	    paths: ["api/**"]
+//	    author_associations: ["MEMBER", "OWNER"]
+type PluginConditions struct {
+	// Branches is a list of globs matched against the base branch of a pull request.
+	// Events of plain issues never match when it is set.
+	Branches []string `json:"branches,omitempty"`
+	// Events is a list of webhook event types, e.g. issue_comment, pull_request.
+	Events []string `json:"events,omitempty"`
+	// Paths is a list of globs, at least one changed file of the pull request must match.
+	// Events of plain issues never match when it is set.
+	Paths []string `json:"paths,omitempty"`
+	// AuthorAssociations is a list of associations of the event sender with the repo,
+	// e.g. OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, FIRST_TIME_CONTRIBUTOR, NONE.
+	AuthorAssociations []string `json:"author_associations,omitempty"`
 }
 
+// IsEmpty returns true if no condition is set.
+func (c PluginConditions) IsEmpty() bool {
+	return len(c.Branches) == 0 && len(c.Events) == 0 && len(c.Paths) == 0 && len(c.AuthorAssociations) == 0
+}
+
+// OwnersConfiguration is the prow compatible OWNERS file
+//
+//	options:
+//	  no_parent_owners: true
+//	approvers: ["alice"]
+//	reviewers: ["bob"]
+//	emeritus_approvers: ["carol"]
+//	required_reviewers: ["dave"]
+//	labels: ["area/foo"]
+//	filters:
+//	  "\\.go$":
+//	    approvers: ["erin"]
 type OwnersConfiguration struct {
-	Owner     string   `json:"owner"`
-	Repo      string   `json:"repo"`
-	Path      string   `json:"path"`
-	Reviewers []string `json:"reviewers"`
-	Approvers []string `json:"approvers"`
+	Owner             string                  `json:"owner"`
+	Repo              string                  `json:"repo"`
+	Path              string                  `json:"path"`
+	Options           OwnersOptions           `json:"options,omitempty"`
+	Reviewers         []string                `json:"reviewers"`
+	Approvers         []string                `json:"approvers"`
+	Labels            []string                `json:"labels,omitempty"`
+	EmeritusApprovers []string                `json:"emeritus_approvers,omitempty"`
+	RequiredReviewers []string                `json:"required_reviewers,omitempty"`
+	Filters           map[string]OwnersFilter `json:"filters,omitempty"`
+}
+
+type OwnersOptions struct {
+	// NoParentOwners stops the owners of parent directories from being inherited.
+	NoParentOwners bool `json:"no_parent_owners,omitempty"`
+}
+
+// OwnersFilter contains the owners of files matching a regex.
+// The regex is matched against the file path relative to the directory of the OWNERS file.
+type OwnersFilter struct {
+	Reviewers         []string `json:"reviewers,omitempty"`
+	Approvers         []string `json:"approvers,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
+	RequiredReviewers []string `json:"required_reviewers,omitempty"`
+}
+
+// OwnersAliases is the prow compatible OWNERS_ALIASES file at the repo root.
+// An alias can be used in OWNERS files in place of a login.
+//
+//	aliases:
+//	  sig-foo:
+//	  - alice
+//	  - "@airconduct/foo-maintainers"
+type OwnersAliases struct {
+	Aliases map[string][]string `json:"aliases"`
 }
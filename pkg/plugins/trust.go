@@ -0,0 +1,28 @@
+package plugins
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// TrustedAssociations are the author associations of users who are trusted, e.g. to run tests.
+var TrustedAssociations = sets.NewString("OWNER", "MEMBER", "COLLABORATOR")
+
+// IsTrusted returns true if the user is a member of the org owning repo or a collaborator of repo.
+// The association of the user from the event is checked first to save API calls, it may be empty.
+func IsTrusted(
+	ctx context.Context, orgClient GitOrgClient, repoClient GitRepoClient,
+	repo GitRepo, login, association string,
+) (bool, error) {
+	if TrustedAssociations.Has(strings.ToUpper(association)) {
+		return true, nil
+	}
+	// Private org members have no MEMBER association
+	member, err := orgClient.IsOrgMember(ctx, repo.Owner.Name, login)
+	if err != nil || member {
+		return member, err
+	}
+	return repoClient.IsCollaborator(ctx, repo, login)
+}
@@ -0,0 +1,44 @@
+package plugins_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+)
+
+var _ = Describe("IsTrusted", func() {
+	var lookups []string
+	orgClient := mock.FakeOrgClient(map[string]interface{}{
+		"IsOrgMember": func(ctx context.Context, org, login string) (bool, error) {
+			lookups = append(lookups, "org:"+org+"/"+login)
+			return login == "member", nil
+		},
+	})
+	repoClient := mock.FakeRepoClient(map[string]interface{}{
+		"IsCollaborator": func(ctx context.Context, repo plugins.GitRepo, login string) (bool, error) {
+			lookups = append(lookups, "collaborator:"+login)
+			return login == "collaborator", nil
+		},
+	})
+	repo := plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}}
+	BeforeEach(func() {
+		lookups = nil
+	})
+
+	DescribeTable("Should check the association, org membership and collaborators",
+		func(login, association string, trusted bool, expectedLookups []string) {
+			ok, err := plugins.IsTrusted(context.TODO(), orgClient, repoClient, repo, login, association)
+			Expect(err).Should(Succeed())
+			Expect(ok).Should(Equal(trusted))
+			Expect(lookups).Should(Equal(expectedLookups))
+		},
+		Entry("trusted association", "someone", "member", true, nil),
+		Entry("private org member", "member", "CONTRIBUTOR", true, []string{"org:airconduct/member"}),
+		Entry("collaborator", "collaborator", "", true, []string{"org:airconduct/collaborator", "collaborator:collaborator"}),
+		Entry("stranger", "stranger", "NONE", false, []string{"org:airconduct/stranger", "collaborator:stranger"}),
+	)
+})
@@ -0,0 +1,147 @@
+// Package releasenotes collects the release notes of merged pull requests.
+package releasenotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+)
+
+const (
+	kindLabelPrefix = "kind/"
+	// KindOther is the kind of pull requests without `kind/*` label.
+	KindOther = "other"
+)
+
+// Options selects the merged pull requests to collect release notes from.
+type Options struct {
+	Repo plugins.GitRepo
+	// Branch is the base branch of the pull requests, empty for all branches.
+	Branch string
+	// From and To are refs, pull requests merged by the commits in From...To are selected.
+	// From is a branch or tag and an empty To means the default branch. They take precedence
+	// over Since and Until.
+	From, To string
+	// Since and Until select pull requests merged in [Since, Until), a zero Until means now.
+	Since, Until time.Time
+}
+
+// Note is the release note of a pull request.
+type Note struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Author string `json:"author"`
+	Kind   string `json:"kind"`
+	Text   string `json:"text"`
+}
+
+// Section contains the notes of one kind.
+type Section struct {
+	Kind  string `json:"kind"`
+	Notes []Note `json:"notes"`
+}
+
+// Notes are release notes grouped by kind, sorted by kind with KindOther last.
+type Notes []Section
+
+// Collect collects the release notes of merged pull requests. Pull requests without a
+// release-note block, or whose release note is NONE, are skipped.
+func Collect(ctx context.Context, client plugins.GitSearchClient, opts Options) (Notes, error) {
+	var (
+		prs []plugins.GitPullRequest
+		err error
+	)
+	if opts.From != "" {
+		prs, err = client.ListMergedPRBetween(ctx, opts.Repo, opts.From, opts.To)
+	} else {
+		prs, err = client.SearchMergedPR(ctx, opts.Repo, opts.Branch, opts.Since, opts.Until)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sections := map[string]*Section{}
+	for _, pr := range prs {
+		if opts.Branch != "" && pr.Base.Ref != opts.Branch {
+			continue
+		}
+		text, ok := plugins.ExtractReleaseNote(pr.Body)
+		if !ok || plugins.IsReleaseNoteNone(text) {
+			continue
+		}
+		note := Note{
+			Number: pr.Number, Title: pr.Title, URL: pr.URL,
+			Author: pr.User.Name, Kind: kindOf(pr.Labels), Text: text,
+		}
+		if _, ok := sections[note.Kind]; !ok {
+			sections[note.Kind] = &Section{Kind: note.Kind}
+		}
+		sections[note.Kind].Notes = append(sections[note.Kind].Notes, note)
+	}
+	notes := Notes{}
+	for _, section := range sections {
+		notes = append(notes, *section)
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if (notes[i].Kind == KindOther) != (notes[j].Kind == KindOther) {
+			return notes[j].Kind == KindOther
+		}
+		return notes[i].Kind < notes[j].Kind
+	})
+	return notes, nil
+}
+
+// kindOf returns the first kind of the `kind/*` labels in alphabetical order.
+func kindOf(labels []plugins.Label) string {
+	var kinds []string
+	for _, label := range labels {
+		if kind := strings.TrimPrefix(label.Name, kindLabelPrefix); kind != label.Name && kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return KindOther
+	}
+	sort.Strings(kinds)
+	return kinds[0]
+}
+
+// Markdown renders notes as a Markdown section per kind.
+func (notes Notes) Markdown() string {
+	b := &strings.Builder{}
+	for i, section := range notes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "## %s\n\n", kindTitle(section.Kind))
+		for _, note := range section.Notes {
+			text := strings.ReplaceAll(note.Text, "\n", "\n  ")
+			fmt.Fprintf(b, "- %s ([#%d](%s), @%s)\n", text, note.Number, note.URL, note.Author)
+		}
+	}
+	return b.String()
+}
+
+// JSON renders notes as indented JSON.
+func (notes Notes) JSON() (string, error) {
+	out, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// kindTitle returns the title of a kind, e.g. "api-change" -> "Api Change".
+func kindTitle(kind string) string {
+	words := strings.FieldsFunc(kind, func(r rune) bool { return r == '-' || r == '_' })
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
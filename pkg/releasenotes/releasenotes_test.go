@@ -0,0 +1,123 @@
+package releasenotes_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/airconduct/kuilei/pkg/plugins"
+	"github.com/airconduct/kuilei/pkg/plugins/mock"
+	"github.com/airconduct/kuilei/pkg/releasenotes"
+)
+
+var _ = Describe("Collect", func() {
+	var (
+		since, until time.Time
+		from, to     string
+	)
+	pr := func(number int, body string, labels ...string) plugins.GitPullRequest {
+		var ls []plugins.Label
+		for _, l := range labels {
+			ls = append(ls, plugins.Label{Name: l})
+		}
+		return plugins.GitPullRequest{
+			Number: number, Title: "title", Body: body, Labels: ls, Base: plugins.GitBranch{Ref: "main"},
+			URL: "https://github.com/airconduct/kuilei/pull/1", User: plugins.GitUser{Name: "alice"},
+		}
+	}
+	backport := pr(7, "```release-note\nBackport.\n```", "kind/bug")
+	backport.Base.Ref = "release-0.1"
+	prs := []plugins.GitPullRequest{
+		pr(1, "```release-note\nAdd foo.\n```", "kind/feature"),
+		pr(2, "```release-note\nFix bar.\nAnd baz.\n```", "kind/bug", "kind/regression"),
+		pr(3, "```release-note\nNONE\n```", "kind/cleanup"),
+		pr(4, "no release note", "kind/feature"),
+		pr(5, "```release-note\nTweak.\n```"),
+		pr(6, "```release-note\nAdd api.\n```", "kind/api-change", "lgtm"),
+		backport,
+	}
+	client := mock.FakeSearchClient(map[string]interface{}{
+		"ListMergedPRBetween": func(ctx context.Context, repo plugins.GitRepo, f, t string) ([]plugins.GitPullRequest, error) {
+			Expect(repo.Name).Should(Equal("kuilei"))
+			from, to = f, t
+			return prs, nil
+		},
+		"SearchMergedPR": func(ctx context.Context, repo plugins.GitRepo, base string, s, u time.Time) ([]plugins.GitPullRequest, error) {
+			Expect(repo.Name).Should(Equal("kuilei"))
+			Expect(base).Should(Equal("main"))
+			since, until = s, u
+			return prs[:6], nil
+		},
+	})
+	opts := releasenotes.Options{
+		Repo:   plugins.GitRepo{Name: "kuilei", Owner: plugins.GitUser{Name: "airconduct"}},
+		Branch: "main",
+	}
+
+	It("Should collect notes between refs", func() {
+		o := opts
+		o.From, o.To = "v0.1.0", "v0.2.0"
+		notes, err := releasenotes.Collect(context.TODO(), client, o)
+		Expect(err).Should(Succeed())
+		Expect(from).Should(Equal("v0.1.0"))
+		Expect(to).Should(Equal("v0.2.0"))
+
+		var kinds []string
+		for _, section := range notes {
+			kinds = append(kinds, section.Kind)
+		}
+		Expect(kinds).Should(Equal([]string{"api-change", "bug", "feature", "other"}))
+		Expect(notes[2].Notes).Should(Equal([]releasenotes.Note{{
+			Number: 1, Title: "title", URL: "https://github.com/airconduct/kuilei/pull/1",
+			Author: "alice", Kind: "feature", Text: "Add foo.",
+		}}))
+
+		Expect(notes.Markdown()).Should(Equal(`## Api Change
+
+- Add api. ([#6](https://github.com/airconduct/kuilei/pull/1), @alice)
+
+## Bug
+
+- Fix bar.
+  And baz. ([#2](https://github.com/airconduct/kuilei/pull/1), @alice)
+
+## Feature
+
+- Add foo. ([#1](https://github.com/airconduct/kuilei/pull/1), @alice)
+
+## Other
+
+- Tweak. ([#5](https://github.com/airconduct/kuilei/pull/1), @alice)
+`))
+		out, err := notes.JSON()
+		Expect(err).Should(Succeed())
+		decoded := releasenotes.Notes{}
+		Expect(json.Unmarshal([]byte(out), &decoded)).Should(Succeed())
+		Expect(decoded).Should(Equal(notes))
+	})
+
+	It("Should collect notes of all branches between refs", func() {
+		o := opts
+		o.Branch, o.From = "", "v0.1.0"
+		notes, err := releasenotes.Collect(context.TODO(), client, o)
+		Expect(err).Should(Succeed())
+		Expect(from).Should(Equal("v0.1.0"))
+		Expect(to).Should(BeEmpty())
+		Expect(notes[1].Kind).Should(Equal("bug"))
+		Expect(notes[1].Notes).Should(HaveLen(2))
+		Expect(notes[1].Notes[1].Number).Should(Equal(7))
+	})
+
+	It("Should collect notes between dates", func() {
+		date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		o := opts
+		o.Since = date
+		_, err := releasenotes.Collect(context.TODO(), client, o)
+		Expect(err).Should(Succeed())
+		Expect(since).Should(Equal(date))
+		Expect(until.IsZero()).Should(BeTrue())
+	})
+})